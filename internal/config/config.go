@@ -1,24 +1,28 @@
 package config
 
 import (
+	"crypto/hkdf"
+	"crypto/sha256"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Database DatabaseConfig
-	Server   ServerConfig
-	JWT      JWTConfig
-	Upload   UploadConfig
-	RateLimit RateLimitConfig
-	Redis    RedisConfig
-	AWS      AWSConfig
-	Email    EmailConfig
+	Database   DatabaseConfig
+	Server     ServerConfig
+	JWT        JWTConfig
+	Upload     UploadConfig
+	RateLimit  RateLimitConfig
+	Redis      RedisConfig
+	AWS        AWSConfig
+	Email      EmailConfig
+	Pagination PaginationConfig
 }
 
 type DatabaseConfig struct {
@@ -33,6 +37,8 @@ type ServerConfig struct {
 	Port        string
 	Environment string
 	FrontendURL string
+	// AllowedOrigins may open WebSocket connections from a browser
+	AllowedOrigins []string
 }
 
 type JWTConfig struct {
@@ -73,6 +79,10 @@ type EmailConfig struct {
 	From     string
 }
 
+type PaginationConfig struct {
+	CursorSecret string // Signs pagination cursors, derived from the JWT secret when unset
+}
+
 var AppConfig *Config
 
 func Load() (*Config, error) {
@@ -106,6 +116,19 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid REDIS_DB: %w", err)
 	}
 
+	jwtSecret := getEnv("JWT_SECRET", "change-this-secret-key")
+	frontendURL := getEnv("FRONTEND_URL", "http://localhost:3000")
+
+	cursorSecret := os.Getenv("CURSOR_SECRET")
+	if cursorSecret == "" {
+		// Never sign cursors with the JWT key itself
+		key, err := hkdf.Key(sha256.New, []byte(jwtSecret), nil, "cursor", 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive cursor secret: %w", err)
+		}
+		cursorSecret = string(key)
+	}
+
 	config := &Config{
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -115,12 +138,13 @@ func Load() (*Config, error) {
 			DBName:   getEnv("DB_NAME", "social_media_db"),
 		},
 		Server: ServerConfig{
-			Port:        getEnv("PORT", "8080"),
-			Environment: getEnv("APP_ENV", "development"),
-			FrontendURL: getEnv("FRONTEND_URL", "http://localhost:3000"),
+			Port:           getEnv("PORT", "8080"),
+			Environment:    getEnv("APP_ENV", "development"),
+			FrontendURL:    frontendURL,
+			AllowedOrigins: parseOrigins(getEnv("ALLOWED_ORIGINS", frontendURL)),
 		},
 		JWT: JWTConfig{
-			Secret: getEnv("JWT_SECRET", "change-this-secret-key"),
+			Secret: jwtSecret,
 			Expiry: jwtExpiry,
 		},
 		Upload: UploadConfig{
@@ -151,6 +175,9 @@ func Load() (*Config, error) {
 			Password: getEnv("SMTP_PASSWORD", ""),
 			From:     getEnv("FROM_EMAIL", "noreply@socialmedia.com"),
 		},
+		Pagination: PaginationConfig{
+			CursorSecret: cursorSecret,
+		},
 	}
 
 	AppConfig = config
@@ -177,4 +204,15 @@ func parseExtensions(extensions string) []string {
 		i = end + 1
 	}
 	return result
-}
\ No newline at end of file
+}
+
+// parseOrigins splits a comma separated list of origins such as https://example.com
+func parseOrigins(origins string) []string {
+	var result []string
+	for _, origin := range strings.Split(origins, ",") {
+		if origin = strings.TrimSuffix(strings.TrimSpace(origin), "/"); origin != "" {
+			result = append(result, origin)
+		}
+	}
+	return result
+}
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+
+	"social-media-backend/internal/models"
+)
+
+// indexStatements holds indexes that GORM tags cannot express, such as partial indexes
+var indexStatements = []string{
+	// One reaction per user and target, see uniqueLikeStatements
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_likes_user_post ON likes (user_id, post_id) WHERE post_id IS NOT NULL`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_likes_user_comment ON likes (user_id, comment_id) WHERE comment_id IS NOT NULL`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_likes_user_message ON likes (user_id, message_id) WHERE message_id IS NOT NULL`,
+	`CREATE INDEX IF NOT EXISTS idx_posts_user_pinned ON posts (user_id, pin_position) WHERE pin_position IS NOT NULL AND deleted_at IS NULL`,
+	// One live repost per user and original post
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_posts_unique_repost ON posts (user_id, original_post_id) WHERE share_type = 'repost' AND deleted_at IS NULL`,
+	// One view per user and story, see uniqueStoryViewStatements
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_story_views_story_user ON story_views (story_id, user_id)`,
+	// Conversation history, latest messages and unread counts
+	`CREATE INDEX IF NOT EXISTS idx_messages_conversation_created ON messages (conversation_id, created_at DESC, id DESC) WHERE deleted_at IS NULL`,
+}
+
+// uniqueLikeStatements drop duplicate reactions left from before uniqueness was enforced,
+// keeping the oldest, and recount the counters they inflated
+var uniqueLikeStatements = []string{
+	`DELETE FROM likes a USING likes b WHERE a.user_id = b.user_id AND a.post_id = b.post_id AND (a.created_at, a.id) > (b.created_at, b.id)`,
+	`DELETE FROM likes a USING likes b WHERE a.user_id = b.user_id AND a.comment_id = b.comment_id AND (a.created_at, a.id) > (b.created_at, b.id)`,
+	`UPDATE posts p SET likes_count = c.total
+		FROM (SELECT p2.id, COUNT(l.id) AS total FROM posts p2 LEFT JOIN likes l ON l.post_id = p2.id GROUP BY p2.id) c
+		WHERE p.id = c.id AND p.likes_count <> c.total`,
+	`UPDATE comments cm SET likes_count = c.total
+		FROM (SELECT c2.id, COUNT(l.id) AS total FROM comments c2 LEFT JOIN likes l ON l.comment_id = c2.id GROUP BY c2.id) c
+		WHERE cm.id = c.id AND cm.likes_count <> c.total`,
+}
+
+// uniqueStoryViewStatements drop repeated story views left from before uniqueness was
+// enforced, keeping the first, and recount the unique viewers of every story
+var uniqueStoryViewStatements = []string{
+	`DELETE FROM story_views a USING story_views b WHERE a.story_id = b.story_id AND a.user_id = b.user_id AND (a.viewed_at, a.id) > (b.viewed_at, b.id)`,
+	// Authors viewing their own stories are not counted
+	`UPDATE stories s SET views_count = c.total
+		FROM (SELECT s2.id, COUNT(v.id) AS total FROM stories s2 LEFT JOIN story_views v ON v.story_id = s2.id AND v.user_id <> s2.user_id GROUP BY s2.id) c
+		WHERE s.id = c.id AND s.views_count <> c.total`,
+}
+
+// directConversationStatements move messages from before conversations existed, which had a
+// single receiver and read flag, into direct conversations with per-participant read cursors
+var directConversationStatements = []string{
+	`ALTER TABLE messages ADD COLUMN conversation_id uuid`,
+	`INSERT INTO conversations (id, kind, direct_key, created_at, updated_at)
+		SELECT gen_random_uuid(), 'direct', LEAST(sender_id, receiver_id)::text || ':' || GREATEST(sender_id, receiver_id)::text, MIN(created_at), MAX(created_at)
+		FROM messages
+		GROUP BY LEAST(sender_id, receiver_id), GREATEST(sender_id, receiver_id)`,
+	`UPDATE messages m SET conversation_id = c.id
+		FROM conversations c
+		WHERE c.direct_key = LEAST(m.sender_id, m.receiver_id)::text || ':' || GREATEST(m.sender_id, m.receiver_id)::text`,
+	// Everything a user sent, and everything they received and read, counts as read
+	`INSERT INTO conversation_participants (id, conversation_id, user_id, role, last_read_message_at, last_read_at, joined_at, created_at, updated_at)
+		SELECT gen_random_uuid(), m.conversation_id, p.user_id, 'member',
+			MAX(m.created_at) FILTER (WHERE m.sender_id = p.user_id OR m.is_read),
+			MAX(COALESCE(m.read_at, m.created_at)) FILTER (WHERE m.sender_id = p.user_id OR m.is_read),
+			MIN(m.created_at), MIN(m.created_at), NOW()
+		FROM messages m
+		CROSS JOIN LATERAL (VALUES (m.sender_id), (m.receiver_id)) AS p(user_id)
+		GROUP BY m.conversation_id, p.user_id`,
+	`UPDATE conversation_participants p SET last_read_message_id = (
+			SELECT m.id FROM messages m
+			WHERE m.conversation_id = p.conversation_id AND m.created_at <= p.last_read_message_at
+			ORDER BY m.created_at DESC, m.id DESC
+			LIMIT 1)
+		WHERE p.last_read_message_at IS NOT NULL`,
+	`ALTER TABLE messages ALTER COLUMN conversation_id SET NOT NULL`,
+	`ALTER TABLE messages DROP COLUMN receiver_id, DROP COLUMN is_read, DROP COLUMN read_at`,
+}
+
+func MigrateDatabase(db *gorm.DB) error {
+	if err := migrateDirectConversations(db); err != nil {
+		return fmt.Errorf("failed to migrate direct messages: %w", err)
+	}
+
+	if err := db.AutoMigrate(
+		&models.User{},
+		&models.Follow{},
+		&models.Block{},
+		&models.Mute{},
+		&models.Post{},
+		&models.Comment{},
+		&models.CommentFilterKeyword{},
+		&models.Like{},
+		&models.Poll{},
+		&models.PollOption{},
+		&models.PollBallot{},
+		&models.PollVote{},
+		&models.SavedPost{},
+		&models.Collection{},
+		&models.CollectionItem{},
+		&models.Hashtag{},
+		&models.HashtagUsage{},
+		&models.HashtagFollow{},
+		&models.Mention{},
+		&models.Story{},
+		&models.StoryView{},
+		&models.StorySticker{},
+		&models.StoryStickerAnswer{},
+		&models.Highlight{},
+		&models.HighlightItem{},
+		&models.Conversation{},
+		&models.ConversationParticipant{},
+		&models.Message{},
+		&models.HiddenMessage{},
+		&models.Notification{},
+	); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	if err := migrateUniqueLikes(db); err != nil {
+		return fmt.Errorf("failed to deduplicate reactions: %w", err)
+	}
+	if err := migrateUniqueStoryViews(db); err != nil {
+		return fmt.Errorf("failed to deduplicate story views: %w", err)
+	}
+
+	for _, stmt := range indexStatements {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+
+	log.Println("Database migrated successfully")
+	return nil
+}
+
+// migrateDirectConversations runs once, on databases whose messages still have a receiver
+func migrateDirectConversations(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&models.Message{}) || migrator.HasColumn(&models.Message{}, "conversation_id") {
+		return nil
+	}
+	if err := db.AutoMigrate(&models.Conversation{}, &models.ConversationParticipant{}); err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range directConversationStatements {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// migrateUniqueLikes runs once, before the unique reaction indexes exist
+func migrateUniqueLikes(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if migrator.HasIndex(&models.Like{}, "idx_likes_user_post") && migrator.HasIndex(&models.Like{}, "idx_likes_user_comment") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range uniqueLikeStatements {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// migrateUniqueStoryViews runs once, before the unique story view index exists
+func migrateUniqueStoryViews(db *gorm.DB) error {
+	if db.Migrator().HasIndex(&models.StoryView{}, "idx_story_views_story_user") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range uniqueStoryViewStatements {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var Redis *redis.Client
+
+// ConnectRedis creates the Redis client. The client reconnects on its own, so an
+// unreachable server is logged rather than treated as fatal: callers fall back to
+// Postgres whenever a Redis command fails.
+func ConnectRedis(config *Config) *redis.Client {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", config.Redis.Host, config.Redis.Port),
+		Password: config.Redis.Password,
+		DB:       config.Redis.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("Redis unavailable, continuing without cache: %v", err)
+	} else {
+		log.Println("Redis connected successfully")
+	}
+
+	Redis = client
+	return client
+}
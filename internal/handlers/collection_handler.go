@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/services"
+	"social-media-backend/internal/utils"
+)
+
+type CollectionHandler struct {
+	collectionService *services.CollectionService
+}
+
+func NewCollectionHandler(collectionService *services.CollectionService) *CollectionHandler {
+	return &CollectionHandler{collectionService: collectionService}
+}
+
+// SavePost handles POST /posts/:id/save
+func (h *CollectionHandler) SavePost(c *gin.Context) {
+	postID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	// The body is optional, a bare POST saves without a collection
+	var req models.SavePostRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	post, err := h.collectionService.SavePost(currentUserID(c), postID, req.CollectionIDs)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post saved", post)
+}
+
+// UnsavePost handles DELETE /posts/:id/save
+func (h *CollectionHandler) UnsavePost(c *gin.Context) {
+	postID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.collectionService.UnsavePost(currentUserID(c), postID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post removed from saved", nil)
+}
+
+// GetSavedPosts handles GET /saved
+func (h *CollectionHandler) GetSavedPosts(c *gin.Context) {
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.collectionService.GetSavedPosts(currentUserID(c), cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
+
+// GetCollections handles GET /collections
+func (h *CollectionHandler) GetCollections(c *gin.Context) {
+	collections, err := h.collectionService.GetCollections(currentUserID(c))
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", collections)
+}
+
+// CreateCollection handles POST /collections
+func (h *CollectionHandler) CreateCollection(c *gin.Context) {
+	var req models.CreateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	collection, err := h.collectionService.CreateCollection(currentUserID(c), req.Name)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Collection created", collection)
+}
+
+// RenameCollection handles PATCH /collections/:id
+func (h *CollectionHandler) RenameCollection(c *gin.Context) {
+	collectionID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	collection, err := h.collectionService.RenameCollection(currentUserID(c), collectionID, req.Name)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Collection renamed", collection)
+}
+
+// ReorderCollections handles PUT /collections/order
+func (h *CollectionHandler) ReorderCollections(c *gin.Context) {
+	var req models.ReorderCollectionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	collections, err := h.collectionService.ReorderCollections(currentUserID(c), req.CollectionIDs)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Collections reordered", collections)
+}
+
+// DeleteCollection handles DELETE /collections/:id
+func (h *CollectionHandler) DeleteCollection(c *gin.Context) {
+	collectionID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.collectionService.DeleteCollection(currentUserID(c), collectionID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Collection deleted", nil)
+}
+
+// GetCollectionPosts handles GET /collections/:id/posts
+func (h *CollectionHandler) GetCollectionPosts(c *gin.Context) {
+	collectionID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.collectionService.GetCollectionPosts(currentUserID(c), collectionID, cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
+
+// AddToCollection handles POST /collections/:id/posts/:post_id
+func (h *CollectionHandler) AddToCollection(c *gin.Context) {
+	collectionID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	postID, ok := parseUUIDParam(c, "post_id")
+	if !ok {
+		return
+	}
+
+	post, err := h.collectionService.AddToCollection(currentUserID(c), collectionID, postID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post added to collection", post)
+}
+
+// RemoveFromCollection handles DELETE /collections/:id/posts/:post_id
+func (h *CollectionHandler) RemoveFromCollection(c *gin.Context) {
+	collectionID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	postID, ok := parseUUIDParam(c, "post_id")
+	if !ok {
+		return
+	}
+
+	if err := h.collectionService.RemoveFromCollection(currentUserID(c), collectionID, postID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post removed from collection", nil)
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/services"
+	"social-media-backend/internal/utils"
+	"social-media-backend/pkg/constants"
+)
+
+type CommentHandler struct {
+	commentService *services.CommentService
+}
+
+func NewCommentHandler(commentService *services.CommentService) *CommentHandler {
+	return &CommentHandler{commentService: commentService}
+}
+
+// CreateComment handles POST /comments
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	var req models.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comment, err := h.commentService.CreateComment(currentUserID(c), &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Comment created successfully", comment)
+}
+
+// GetComment handles GET /comments/:id
+func (h *CommentHandler) GetComment(c *gin.Context) {
+	commentID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	comment, err := h.commentService.GetComment(currentUserID(c), commentID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", comment)
+}
+
+// UpdateComment handles PUT /comments/:id
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	commentID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comment, err := h.commentService.UpdateComment(currentUserID(c), commentID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Comment updated successfully", comment)
+}
+
+// DeleteComment handles DELETE /comments/:id
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	commentID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.commentService.DeleteComment(currentUserID(c), commentID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Comment deleted successfully", nil)
+}
+
+// ListComments handles GET /posts/:id/comments?sort=newest|oldest|top
+func (h *CommentHandler) ListComments(c *gin.Context) {
+	postID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	sort, ok := commentSort(c, constants.CommentSortNewest)
+	if !ok {
+		return
+	}
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.commentService.ListComments(currentUserID(c), postID, sort, cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
+
+// ListReplies handles GET /comments/:id/replies?sort=newest|oldest|top
+func (h *CommentHandler) ListReplies(c *gin.Context) {
+	commentID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	sort, ok := commentSort(c, constants.CommentSortOldest)
+	if !ok {
+		return
+	}
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.commentService.ListReplies(currentUserID(c), commentID, sort, cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
+
+// commentSort reads the sort query param. Cursors are only valid for the sort they were issued for.
+func commentSort(c *gin.Context, defaultSort string) (string, bool) {
+	sort := c.DefaultQuery("sort", defaultSort)
+	switch sort {
+	case constants.CommentSortNewest, constants.CommentSortOldest, constants.CommentSortTop:
+		return sort, true
+	}
+	utils.ErrorResponse(c, http.StatusBadRequest, "invalid sort")
+	return "", false
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/services"
+	"social-media-backend/internal/utils"
+	"social-media-backend/pkg/constants"
+)
+
+type CommentModerationHandler struct {
+	moderationService *services.CommentModerationService
+}
+
+func NewCommentModerationHandler(moderationService *services.CommentModerationService) *CommentModerationHandler {
+	return &CommentModerationHandler{moderationService: moderationService}
+}
+
+// UpdateCommentSettings handles PATCH /posts/:id/comment-settings
+func (h *CommentModerationHandler) UpdateCommentSettings(c *gin.Context) {
+	postID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCommentSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	post, err := h.moderationService.UpdateCommentSettings(currentUserID(c), postID, req.CommentPolicy)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Comment settings updated", post)
+}
+
+// ListHeldComments handles GET /posts/:id/comments/held?status=pending_review|hidden
+func (h *CommentModerationHandler) ListHeldComments(c *gin.Context) {
+	postID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	status := c.DefaultQuery("status", constants.CommentStatusPendingReview)
+	if status != constants.CommentStatusPendingReview && status != constants.CommentStatusHidden {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid status")
+		return
+	}
+
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.moderationService.ListHeldComments(currentUserID(c), postID, status, cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
+
+// HideComment handles POST /comments/:id/hide
+func (h *CommentModerationHandler) HideComment(c *gin.Context) {
+	commentID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	comment, err := h.moderationService.HideComment(currentUserID(c), commentID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Comment hidden", comment)
+}
+
+// ApproveComment handles POST /comments/:id/approve
+func (h *CommentModerationHandler) ApproveComment(c *gin.Context) {
+	commentID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	comment, err := h.moderationService.ApproveComment(currentUserID(c), commentID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Comment approved", comment)
+}
+
+// PinComment handles POST /comments/:id/pin
+func (h *CommentModerationHandler) PinComment(c *gin.Context) {
+	commentID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	comment, err := h.moderationService.PinComment(currentUserID(c), commentID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Comment pinned", comment)
+}
+
+// UnpinComment handles DELETE /comments/:id/pin
+func (h *CommentModerationHandler) UnpinComment(c *gin.Context) {
+	commentID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.moderationService.UnpinComment(currentUserID(c), commentID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Comment unpinned", nil)
+}
+
+// GetFilterKeywords handles GET /users/me/comment-filters
+func (h *CommentModerationHandler) GetFilterKeywords(c *gin.Context) {
+	keywords, err := h.moderationService.GetFilterKeywords(currentUserID(c))
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", keywords)
+}
+
+// AddFilterKeyword handles POST /users/me/comment-filters
+func (h *CommentModerationHandler) AddFilterKeyword(c *gin.Context) {
+	var req models.AddFilterKeywordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	keyword, err := h.moderationService.AddFilterKeyword(currentUserID(c), req.Keyword)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Keyword added", keyword)
+}
+
+// DeleteFilterKeyword handles DELETE /users/me/comment-filters/:id
+func (h *CommentModerationHandler) DeleteFilterKeyword(c *gin.Context) {
+	keywordID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.moderationService.DeleteFilterKeyword(currentUserID(c), keywordID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Keyword removed", nil)
+}
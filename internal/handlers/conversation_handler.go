@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/services"
+	"social-media-backend/internal/utils"
+)
+
+type ConversationHandler struct {
+	conversationService *services.ConversationService
+}
+
+func NewConversationHandler(conversationService *services.ConversationService) *ConversationHandler {
+	return &ConversationHandler{conversationService: conversationService}
+}
+
+// CreateConversation handles POST /conversations
+func (h *ConversationHandler) CreateConversation(c *gin.Context) {
+	var req models.CreateConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	conversation, err := h.conversationService.CreateConversation(currentUserID(c), &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Conversation created", conversation)
+}
+
+// GetConversations handles GET /conversations
+func (h *ConversationHandler) GetConversations(c *gin.Context) {
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.conversationService.GetConversations(currentUserID(c), cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
+
+// GetMessageRequests handles GET /conversations/requests
+func (h *ConversationHandler) GetMessageRequests(c *gin.Context) {
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.conversationService.GetMessageRequests(currentUserID(c), cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
+
+// GetConversation handles GET /conversations/:id
+func (h *ConversationHandler) GetConversation(c *gin.Context) {
+	conversationID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	conversation, err := h.conversationService.GetConversation(currentUserID(c), conversationID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", conversation)
+}
+
+// UpdateConversation handles PUT /conversations/:id
+func (h *ConversationHandler) UpdateConversation(c *gin.Context) {
+	conversationID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	conversation, err := h.conversationService.UpdateConversation(currentUserID(c), conversationID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Conversation updated", conversation)
+}
+
+// SetAvatar handles PUT /conversations/:id/avatar, a multipart form with the image in "avatar"
+func (h *ConversationHandler) SetAvatar(c *gin.Context) {
+	conversationID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	file, err := c.FormFile("avatar")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "avatar image is required")
+		return
+	}
+
+	conversation, err := h.conversationService.SetAvatar(c.Request.Context(), currentUserID(c), conversationID, file)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Group photo updated", conversation)
+}
+
+// AddParticipants handles POST /conversations/:id/participants
+func (h *ConversationHandler) AddParticipants(c *gin.Context) {
+	conversationID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.AddParticipantsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	conversation, err := h.conversationService.AddParticipants(currentUserID(c), conversationID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Participants added", conversation)
+}
+
+// RemoveParticipant handles DELETE /conversations/:id/participants/:user_id
+func (h *ConversationHandler) RemoveParticipant(c *gin.Context) {
+	conversationID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	targetID, ok := parseUUIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+
+	if err := h.conversationService.RemoveParticipant(currentUserID(c), conversationID, targetID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Participant removed", nil)
+}
+
+// UpdateParticipantRole handles PUT /conversations/:id/participants/:user_id/role
+func (h *ConversationHandler) UpdateParticipantRole(c *gin.Context) {
+	conversationID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	targetID, ok := parseUUIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateParticipantRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.conversationService.UpdateParticipantRole(currentUserID(c), conversationID, targetID, req.Role); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Participant role updated", nil)
+}
+
+// LeaveConversation handles POST /conversations/:id/leave
+func (h *ConversationHandler) LeaveConversation(c *gin.Context) {
+	conversationID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.conversationService.LeaveConversation(currentUserID(c), conversationID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Left the conversation", nil)
+}
+
+// MarkRead handles POST /conversations/:id/read
+func (h *ConversationHandler) MarkRead(c *gin.Context) {
+	conversationID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	// The body is optional; without a message the cursor moves to the latest message
+	var req models.MarkReadRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if err := h.conversationService.MarkRead(currentUserID(c), conversationID, req.MessageID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Conversation marked as read", nil)
+}
+
+// AcceptRequest handles POST /conversations/:id/accept
+func (h *ConversationHandler) AcceptRequest(c *gin.Context) {
+	conversationID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	conversation, err := h.conversationService.AcceptRequest(currentUserID(c), conversationID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Message request accepted", conversation)
+}
+
+// DeclineRequest handles POST /conversations/:id/decline
+func (h *ConversationHandler) DeclineRequest(c *gin.Context) {
+	conversationID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.conversationService.DeclineRequest(currentUserID(c), conversationID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Message request declined", nil)
+}
+
+// BlockRequest handles POST /conversations/:id/block
+func (h *ConversationHandler) BlockRequest(c *gin.Context) {
+	conversationID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.conversationService.BlockRequest(currentUserID(c), conversationID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Message request declined and sender blocked", nil)
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+
+	"social-media-backend/internal/services"
+	"social-media-backend/internal/utils"
+)
+
+type ExploreHandler struct {
+	exploreService *services.ExploreService
+}
+
+func NewExploreHandler(exploreService *services.ExploreService) *ExploreHandler {
+	return &ExploreHandler{exploreService: exploreService}
+}
+
+// GetExplore handles GET /explore
+func (h *ExploreHandler) GetExplore(c *gin.Context) {
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.exploreService.GetExplore(c.Request.Context(), currentUserID(c), requestRegion(c), cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
+
+// requestRegion reads the region from the query string, falling back to the region of
+// the preferred Accept-Language locale, such as US for en-US
+func requestRegion(c *gin.Context) string {
+	if region := c.Query("region"); region != "" {
+		return region
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	if err != nil || len(tags) == 0 {
+		return ""
+	}
+	if region, confidence := tags[0].Region(); confidence == language.Exact {
+		return region.String()
+	}
+	return ""
+}
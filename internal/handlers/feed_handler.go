@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"social-media-backend/internal/services"
+	"social-media-backend/internal/utils"
+	"social-media-backend/pkg/constants"
+	apperrors "social-media-backend/pkg/errors"
+	"social-media-backend/pkg/pagination"
+)
+
+type FeedHandler struct {
+	feedService *services.FeedService
+}
+
+func NewFeedHandler(feedService *services.FeedService) *FeedHandler {
+	return &FeedHandler{feedService: feedService}
+}
+
+// GetHomeFeed handles GET /feed?mode=chronological|ranked
+func (h *FeedHandler) GetHomeFeed(c *gin.Context) {
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	var page *pagination.Page
+	switch c.DefaultQuery("mode", constants.FeedModeChronological) {
+	case constants.FeedModeChronological:
+		page, err = h.feedService.GetHomeFeed(c.Request.Context(), currentUserID(c), cursor, limit)
+	case constants.FeedModeRanked:
+		page, err = h.feedService.GetRankedFeed(c.Request.Context(), currentUserID(c), cursor, limit)
+	default:
+		err = apperrors.ErrInvalidFeedMode
+	}
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"social-media-backend/internal/services"
+	"social-media-backend/internal/utils"
+	"social-media-backend/pkg/constants"
+)
+
+type HashtagHandler struct {
+	hashtagService  *services.HashtagService
+	trendingService *services.TrendingService
+}
+
+func NewHashtagHandler(hashtagService *services.HashtagService, trendingService *services.TrendingService) *HashtagHandler {
+	return &HashtagHandler{hashtagService: hashtagService, trendingService: trendingService}
+}
+
+// GetTrending handles GET /hashtags/trending
+func (h *HashtagHandler) GetTrending(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	window := c.DefaultQuery("window", constants.TrendingWindow24h)
+
+	hashtags, err := h.trendingService.GetTrending(c.Request.Context(), window, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", hashtags)
+}
+
+// GetHashtag handles GET /hashtags/:name
+func (h *HashtagHandler) GetHashtag(c *gin.Context) {
+	hashtag, err := h.hashtagService.GetHashtag(currentUserID(c), c.Param("name"))
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", hashtag)
+}
+
+// GetHashtagUsage handles GET /hashtags/:name/usage
+func (h *HashtagHandler) GetHashtagUsage(c *gin.Context) {
+	window := c.DefaultQuery("window", constants.TrendingWindow24h)
+
+	points, err := h.hashtagService.GetHashtagUsage(c.Param("name"), window)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", points)
+}
+
+// GetHashtagPosts handles GET /hashtags/:name/posts
+func (h *HashtagHandler) GetHashtagPosts(c *gin.Context) {
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.hashtagService.GetHashtagPosts(currentUserID(c), c.Param("name"), cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
+
+// FollowHashtag handles POST /hashtags/:name/follow
+func (h *HashtagHandler) FollowHashtag(c *gin.Context) {
+	hashtag, err := h.hashtagService.FollowHashtag(currentUserID(c), c.Param("name"))
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Hashtag followed", hashtag)
+}
+
+// UnfollowHashtag handles DELETE /hashtags/:name/follow
+func (h *HashtagHandler) UnfollowHashtag(c *gin.Context) {
+	if err := h.hashtagService.UnfollowHashtag(currentUserID(c), c.Param("name")); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Hashtag unfollowed", nil)
+}
+
+// GetFollowedHashtags handles GET /users/me/hashtags
+func (h *HashtagHandler) GetFollowedHashtags(c *gin.Context) {
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.hashtagService.GetFollowedHashtags(currentUserID(c), cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
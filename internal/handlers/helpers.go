@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"social-media-backend/internal/middleware"
+	"social-media-backend/internal/utils"
+	apperrors "social-media-backend/pkg/errors"
+	"social-media-backend/pkg/pagination"
+)
+
+// currentUserID returns the authenticated user, or uuid.Nil for anonymous requests
+func currentUserID(c *gin.Context) uuid.UUID {
+	if value, exists := c.Get(middleware.ContextUserID); exists {
+		if userID, ok := value.(uuid.UUID); ok {
+			return userID
+		}
+	}
+	return uuid.Nil
+}
+
+// parseUUIDParam reads a UUID path parameter and writes a 400 response if it is malformed
+func parseUUIDParam(c *gin.Context, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid "+name)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// cursorParams reads the cursor and limit query parameters
+func cursorParams(c *gin.Context) (*pagination.Cursor, int, error) {
+	return pagination.Parse(c.Query("cursor"), c.Query("limit"))
+}
+
+// handleServiceError maps service errors to HTTP status codes
+func handleServiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, apperrors.ErrUnauthorized),
+		errors.Is(err, apperrors.ErrInvalidToken),
+		errors.Is(err, apperrors.ErrTokenExpired):
+		utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, apperrors.ErrUnauthorizedAction),
+		errors.Is(err, apperrors.ErrPrivateAccount),
+		errors.Is(err, apperrors.ErrCommentsDisabled),
+		errors.Is(err, apperrors.ErrCommentsRestricted),
+		errors.Is(err, apperrors.ErrStoryRepliesDisabled),
+		errors.Is(err, apperrors.ErrStoryRepliesRestricted),
+		errors.Is(err, apperrors.ErrCannotMessageUser):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, apperrors.ErrNotFound),
+		errors.Is(err, apperrors.ErrUserNotFound),
+		errors.Is(err, apperrors.ErrPostNotFound),
+		errors.Is(err, apperrors.ErrCommentNotFound),
+		errors.Is(err, apperrors.ErrMessageNotFound),
+		errors.Is(err, apperrors.ErrConversationNotFound),
+		errors.Is(err, apperrors.ErrStoryNotFound),
+		errors.Is(err, apperrors.ErrHighlightNotFound),
+		errors.Is(err, apperrors.ErrStickerNotFound),
+		errors.Is(err, apperrors.ErrCollectionNotFound),
+		errors.Is(err, apperrors.ErrPollNotFound),
+		errors.Is(err, apperrors.ErrHashtagNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, apperrors.ErrPostAlreadyPinned),
+		errors.Is(err, apperrors.ErrPinLimitReached),
+		errors.Is(err, apperrors.ErrAlreadyLiked),
+		errors.Is(err, apperrors.ErrAlreadyFollowing),
+		errors.Is(err, apperrors.ErrCollectionNameTaken),
+		errors.Is(err, apperrors.ErrAlreadyReposted),
+		errors.Is(err, apperrors.ErrAlreadyVoted),
+		errors.Is(err, apperrors.ErrAlreadyAnswered),
+		errors.Is(err, apperrors.ErrAlreadyParticipant),
+		errors.Is(err, apperrors.ErrKeywordAlreadyExists):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, apperrors.ErrPostArchived),
+		errors.Is(err, apperrors.ErrPostNotArchived),
+		errors.Is(err, apperrors.ErrPostNotPinned),
+		errors.Is(err, apperrors.ErrPostNotSaved),
+		errors.Is(err, apperrors.ErrNotReposted),
+		errors.Is(err, apperrors.ErrCannotSharePost),
+		errors.Is(err, apperrors.ErrCannotEditRepost),
+		errors.Is(err, apperrors.ErrPollClosed),
+		errors.Is(err, apperrors.ErrInvalidPollOption),
+		errors.Is(err, apperrors.ErrInvalidPollDuration),
+		errors.Is(err, pagination.ErrInvalidCursor),
+		errors.Is(err, apperrors.ErrNotLiked),
+		errors.Is(err, apperrors.ErrInvalidReaction),
+		errors.Is(err, apperrors.ErrInvalidLikeTarget),
+		errors.Is(err, apperrors.ErrInvalidParentComment),
+		errors.Is(err, apperrors.ErrCannotPinComment),
+		errors.Is(err, apperrors.ErrKeywordLimitReached),
+		errors.Is(err, apperrors.ErrInvalidTrendingWindow),
+		errors.Is(err, apperrors.ErrInvalidFeedMode),
+		errors.Is(err, apperrors.ErrNotFollowing),
+		errors.Is(err, apperrors.ErrCannotFollowSelf),
+		errors.Is(err, apperrors.ErrCannotBlockSelf),
+		errors.Is(err, apperrors.ErrCannotMuteSelf),
+		errors.Is(err, apperrors.ErrCannotMessageSelf),
+		errors.Is(err, apperrors.ErrEmptyMessage),
+		errors.Is(err, apperrors.ErrNotGroupConversation),
+		errors.Is(err, apperrors.ErrConversationFull),
+		errors.Is(err, apperrors.ErrNotParticipant),
+		errors.Is(err, apperrors.ErrLastAdmin),
+		errors.Is(err, apperrors.ErrNotMessageRequest),
+		errors.Is(err, apperrors.ErrStoryExpired),
+		errors.Is(err, apperrors.ErrStoryViewersUnavailable),
+		errors.Is(err, apperrors.ErrStoryMediaUnavailable),
+		errors.Is(err, apperrors.ErrInvalidStoryReaction),
+		errors.Is(err, apperrors.ErrInvalidSticker),
+		errors.Is(err, apperrors.ErrInvalidStickerAnswer),
+		errors.Is(err, apperrors.ErrHighlightFull),
+		errors.Is(err, apperrors.ErrStoryNotInHighlight),
+		errors.Is(err, apperrors.ErrInvalidFileType),
+		errors.Is(err, apperrors.ErrFileTooLarge),
+		errors.Is(err, apperrors.ErrInvalidInput),
+		errors.Is(err, apperrors.ErrValidationFailed),
+		errors.Is(err, apperrors.ErrBadRequest):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, apperrors.ErrInternalServer.Error())
+	}
+}
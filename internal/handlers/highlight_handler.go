@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/services"
+	"social-media-backend/internal/utils"
+)
+
+type HighlightHandler struct {
+	highlightService *services.HighlightService
+}
+
+func NewHighlightHandler(highlightService *services.HighlightService) *HighlightHandler {
+	return &HighlightHandler{highlightService: highlightService}
+}
+
+// CreateHighlight handles POST /highlights
+func (h *HighlightHandler) CreateHighlight(c *gin.Context) {
+	var req models.CreateHighlightRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	highlight, err := h.highlightService.CreateHighlight(currentUserID(c), &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Highlight created", highlight)
+}
+
+// GetHighlight handles GET /highlights/:id
+func (h *HighlightHandler) GetHighlight(c *gin.Context) {
+	highlightID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	highlight, err := h.highlightService.GetHighlight(currentUserID(c), highlightID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", highlight)
+}
+
+// GetUserHighlights handles GET /users/:id/highlights
+func (h *HighlightHandler) GetUserHighlights(c *gin.Context) {
+	userID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	highlights, err := h.highlightService.GetUserHighlights(currentUserID(c), userID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", highlights)
+}
+
+// UpdateHighlight handles PATCH /highlights/:id
+func (h *HighlightHandler) UpdateHighlight(c *gin.Context) {
+	highlightID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateHighlightRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	highlight, err := h.highlightService.UpdateHighlight(c.Request.Context(), currentUserID(c), highlightID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Highlight updated", highlight)
+}
+
+// SetCover handles PUT /highlights/:id/cover, a multipart form with the image in "cover"
+func (h *HighlightHandler) SetCover(c *gin.Context) {
+	highlightID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	file, err := c.FormFile("cover")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "cover image is required")
+		return
+	}
+
+	highlight, err := h.highlightService.SetCover(c.Request.Context(), currentUserID(c), highlightID, file)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Highlight cover updated", highlight)
+}
+
+// DeleteHighlight handles DELETE /highlights/:id
+func (h *HighlightHandler) DeleteHighlight(c *gin.Context) {
+	highlightID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.highlightService.DeleteHighlight(c.Request.Context(), currentUserID(c), highlightID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Highlight deleted", nil)
+}
+
+// AddStories handles POST /highlights/:id/stories
+func (h *HighlightHandler) AddStories(c *gin.Context) {
+	highlightID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.HighlightStoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	highlight, err := h.highlightService.AddStories(currentUserID(c), highlightID, req.StoryIDs)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Stories added to highlight", highlight)
+}
+
+// ReorderStories handles PUT /highlights/:id/stories/order
+func (h *HighlightHandler) ReorderStories(c *gin.Context) {
+	highlightID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.HighlightStoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	highlight, err := h.highlightService.ReorderStories(currentUserID(c), highlightID, req.StoryIDs)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Highlight reordered", highlight)
+}
+
+// RemoveStory handles DELETE /highlights/:id/stories/:story_id
+func (h *HighlightHandler) RemoveStory(c *gin.Context) {
+	highlightID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	storyID, ok := parseUUIDParam(c, "story_id")
+	if !ok {
+		return
+	}
+
+	if err := h.highlightService.RemoveStory(currentUserID(c), highlightID, storyID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Story removed from highlight", nil)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/services"
+	"social-media-backend/internal/utils"
+)
+
+type MessageHandler struct {
+	messageService *services.MessageService
+}
+
+func NewMessageHandler(messageService *services.MessageService) *MessageHandler {
+	return &MessageHandler{messageService: messageService}
+}
+
+// SendDirectMessage handles POST /messages, as JSON or as a multipart form with the media
+// file in "media"
+func (h *MessageHandler) SendDirectMessage(c *gin.Context) {
+	var req models.SendMessageRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Media is optional, so a missing file is not an error
+	file, _ := c.FormFile("media")
+
+	message, err := h.messageService.SendDirectMessage(c.Request.Context(), currentUserID(c), &req, file)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Message sent", message)
+}
+
+// SendMessage handles POST /conversations/:id/messages, as JSON or as a multipart form with
+// the media file in "media"
+func (h *MessageHandler) SendMessage(c *gin.Context) {
+	conversationID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.ConversationMessageRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Media is optional, so a missing file is not an error
+	file, _ := c.FormFile("media")
+
+	message, err := h.messageService.SendMessage(c.Request.Context(), currentUserID(c), conversationID, &req, file)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Message sent", message)
+}
+
+// GetMessages handles GET /conversations/:id/messages
+func (h *MessageHandler) GetMessages(c *gin.Context) {
+	conversationID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.messageService.GetMessages(currentUserID(c), conversationID, cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
+
+// DeleteMessage handles DELETE /messages/:id, removing the message for the current user only
+func (h *MessageHandler) DeleteMessage(c *gin.Context) {
+	messageID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.messageService.DeleteMessage(currentUserID(c), messageID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Message deleted", nil)
+}
+
+// UnsendMessage handles POST /messages/:id/unsend, removing the message for every participant
+func (h *MessageHandler) UnsendMessage(c *gin.Context) {
+	messageID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.messageService.UnsendMessage(c.Request.Context(), currentUserID(c), messageID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Message unsent", nil)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/services"
+	"social-media-backend/internal/utils"
+)
+
+type PollHandler struct {
+	pollService *services.PollService
+}
+
+func NewPollHandler(pollService *services.PollService) *PollHandler {
+	return &PollHandler{pollService: pollService}
+}
+
+// Vote handles POST /posts/:id/poll/votes
+func (h *PollHandler) Vote(c *gin.Context) {
+	postID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.VotePollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	post, err := h.pollService.Vote(currentUserID(c), postID, req.OptionIDs)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Vote recorded", post)
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/services"
+	"social-media-backend/internal/utils"
+)
+
+type PostHandler struct {
+	postService *services.PostService
+}
+
+func NewPostHandler(postService *services.PostService) *PostHandler {
+	return &PostHandler{postService: postService}
+}
+
+// CreatePost handles POST /posts
+func (h *PostHandler) CreatePost(c *gin.Context) {
+	var req models.CreatePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	post, err := h.postService.CreatePost(currentUserID(c), &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Post created successfully", post)
+}
+
+// GetPost handles GET /posts/:id
+func (h *PostHandler) GetPost(c *gin.Context) {
+	postID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	post, err := h.postService.GetPost(currentUserID(c), postID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", post)
+}
+
+// UpdatePost handles PUT /posts/:id
+func (h *PostHandler) UpdatePost(c *gin.Context) {
+	postID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	post, err := h.postService.UpdatePost(currentUserID(c), postID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post updated successfully", post)
+}
+
+// DeletePost handles DELETE /posts/:id
+func (h *PostHandler) DeletePost(c *gin.Context) {
+	postID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.postService.DeletePost(currentUserID(c), postID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post deleted successfully", nil)
+}
+
+// GetUserPosts handles GET /users/:id/posts
+func (h *PostHandler) GetUserPosts(c *gin.Context) {
+	userID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.postService.GetUserPosts(currentUserID(c), userID, cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
+
+// ArchivePost handles POST /posts/:id/archive
+func (h *PostHandler) ArchivePost(c *gin.Context) {
+	postID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	post, err := h.postService.ArchivePost(currentUserID(c), postID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post archived", post)
+}
+
+// UnarchivePost handles DELETE /posts/:id/archive
+func (h *PostHandler) UnarchivePost(c *gin.Context) {
+	postID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	post, err := h.postService.UnarchivePost(currentUserID(c), postID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post restored", post)
+}
+
+// GetArchivedPosts handles GET /posts/archived
+func (h *PostHandler) GetArchivedPosts(c *gin.Context) {
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.postService.GetArchivedPosts(currentUserID(c), cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
+
+// PinPost handles POST /posts/:id/pin
+func (h *PostHandler) PinPost(c *gin.Context) {
+	postID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	post, err := h.postService.PinPost(currentUserID(c), postID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post pinned", post)
+}
+
+// UnpinPost handles DELETE /posts/:id/pin
+func (h *PostHandler) UnpinPost(c *gin.Context) {
+	postID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.postService.UnpinPost(currentUserID(c), postID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Post unpinned", nil)
+}
+
+// ReorderPinnedPosts handles PUT /posts/pinned
+func (h *PostHandler) ReorderPinnedPosts(c *gin.Context) {
+	var req models.ReorderPinnedPostsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	posts, err := h.postService.ReorderPinnedPosts(currentUserID(c), req.PostIDs)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pinned posts reordered", posts)
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/services"
+	"social-media-backend/internal/utils"
+	"social-media-backend/pkg/constants"
+)
+
+// ReactionHandler serves reactions for posts, comments and messages. Each method
+// returns a handler bound to one target kind, the target ID comes from the :id param.
+type ReactionHandler struct {
+	reactionService *services.ReactionService
+}
+
+func NewReactionHandler(reactionService *services.ReactionService) *ReactionHandler {
+	return &ReactionHandler{reactionService: reactionService}
+}
+
+// React handles PUT /{posts,comments,messages}/:id/reactions
+func (h *ReactionHandler) React(kind string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		targetID, ok := parseUUIDParam(c, "id")
+		if !ok {
+			return
+		}
+
+		var req models.ReactRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err := h.reactionService.React(currentUserID(c), kind, targetID, req.Type); err != nil {
+			handleServiceError(c, err)
+			return
+		}
+
+		utils.SuccessResponse(c, http.StatusOK, "Reaction saved", gin.H{"type": req.Type})
+	}
+}
+
+// RemoveReaction handles DELETE /{posts,comments,messages}/:id/reactions
+func (h *ReactionHandler) RemoveReaction(kind string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		targetID, ok := parseUUIDParam(c, "id")
+		if !ok {
+			return
+		}
+
+		if err := h.reactionService.RemoveReaction(currentUserID(c), kind, targetID); err != nil {
+			handleServiceError(c, err)
+			return
+		}
+
+		utils.SuccessResponse(c, http.StatusOK, "Reaction removed", nil)
+	}
+}
+
+// ListReactions handles GET /{posts,comments,messages}/:id/reactions?type=
+func (h *ReactionHandler) ListReactions(kind string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		targetID, ok := parseUUIDParam(c, "id")
+		if !ok {
+			return
+		}
+
+		reactionType := c.Query("type")
+		if reactionType != "" && !isReactionType(reactionType) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "invalid reaction type")
+			return
+		}
+
+		cursor, limit, err := cursorParams(c)
+		if err != nil {
+			handleServiceError(c, err)
+			return
+		}
+
+		page, err := h.reactionService.ListReactions(currentUserID(c), kind, targetID, reactionType, cursor, limit)
+		if err != nil {
+			handleServiceError(c, err)
+			return
+		}
+
+		utils.SuccessResponse(c, http.StatusOK, "", page)
+	}
+}
+
+// Like handles POST /likes?idempotent=
+func (h *ReactionHandler) Like(c *gin.Context) {
+	req, ok := bindLikeRequest(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.reactionService.Like(currentUserID(c), req, isIdempotent(c))
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Liked", status)
+}
+
+// Unlike handles DELETE /likes?idempotent=, the target may be sent as a body or as query params
+func (h *ReactionHandler) Unlike(c *gin.Context) {
+	req, ok := bindLikeRequest(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.reactionService.Unlike(currentUserID(c), req, isIdempotent(c))
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Unliked", status)
+}
+
+// bindLikeRequest reads the target from a JSON body, or from post_id and comment_id
+// query params for clients that cannot send a body with DELETE
+func bindLikeRequest(c *gin.Context) (*models.LikeRequest, bool) {
+	var req models.LikeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return nil, false
+		}
+		return &req, true
+	}
+
+	for name, target := range map[string]**uuid.UUID{"post_id": &req.PostID, "comment_id": &req.CommentID} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "invalid "+name)
+			return nil, false
+		}
+		*target = &id
+	}
+	return &req, true
+}
+
+func isIdempotent(c *gin.Context) bool {
+	idempotent, _ := strconv.ParseBool(c.Query("idempotent"))
+	return idempotent
+}
+
+func isReactionType(value string) bool {
+	for _, reactionType := range constants.ReactionTypes {
+		if reactionType == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/net/websocket"
+
+	"social-media-backend/internal/middleware"
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/services"
+	"social-media-backend/internal/utils"
+	"social-media-backend/pkg/constants"
+	apperrors "social-media-backend/pkg/errors"
+)
+
+type RealtimeHandler struct {
+	hub                 *services.RealtimeHub
+	messageService      *services.MessageService
+	conversationService *services.ConversationService
+	allowedOrigins      []string
+}
+
+func NewRealtimeHandler(hub *services.RealtimeHub, messageService *services.MessageService, conversationService *services.ConversationService, allowedOrigins []string) *RealtimeHandler {
+	return &RealtimeHandler{hub: hub, messageService: messageService, conversationService: conversationService, allowedOrigins: allowedOrigins}
+}
+
+// Connect handles GET /ws, upgrading to a WebSocket that streams messages, read receipts and
+// typing indicators. Clients reconnecting pass the last message they received in
+// last_message_id to have everything after it replayed first; replayed messages may repeat
+// live ones, so clients drop duplicates by ID. The connection closes when the token expires.
+// Browsers may only connect from the allowed origins. The server sends a ping every
+// RealtimePingInterval and closes connections it heard nothing from, pongs included, for
+// RealtimeReadTimeout.
+func (h *RealtimeHandler) Connect(c *gin.Context) {
+	value, _ := c.Get(middleware.ContextClaims)
+	claims, ok := value.(*utils.Claims)
+	if !ok {
+		utils.ErrorResponse(c, http.StatusUnauthorized, apperrors.ErrUnauthorized.Error())
+		return
+	}
+
+	var lastMessageID *uuid.UUID
+	if raw := c.Query("last_message_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "invalid last_message_id")
+			return
+		}
+		lastMessageID = &id
+	}
+
+	server := websocket.Server{
+		Handshake: h.checkOrigin,
+		Handler: func(ws *websocket.Conn) {
+			h.serve(ws, claims, lastMessageID)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// checkOrigin rejects browsers connecting from other sites. Clients that are not browsers
+// send no origin and are let through, the token authenticates them.
+func (h *RealtimeHandler) checkOrigin(config *websocket.Config, req *http.Request) error {
+	origin := req.Header.Get("Origin")
+	if origin != "" && !slices.Contains(h.allowedOrigins, origin) {
+		return errors.New("origin not allowed")
+	}
+	return nil
+}
+
+func (h *RealtimeHandler) serve(ws *websocket.Conn, claims *utils.Claims, lastMessageID *uuid.UUID) {
+	defer ws.Close()
+
+	// Registering before the replay means nothing sent in between is missed
+	client := h.hub.Register(claims.UserID)
+	defer h.hub.Unregister(client)
+
+	var expired <-chan time.Time
+	if claims.ExpiresAt != nil {
+		timer := time.NewTimer(time.Until(claims.ExpiresAt.Time))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	ping := time.NewTicker(constants.RealtimePingInterval)
+	defer ping.Stop()
+
+	disconnected := make(chan struct{})
+	go func() {
+		defer close(disconnected)
+		h.readEvents(ws, client)
+	}()
+
+	if lastMessageID != nil {
+		h.replay(ws, client.UserID, *lastMessageID)
+	}
+
+	for {
+		select {
+		case <-client.Done():
+			return
+		case <-disconnected:
+			return
+		case <-expired:
+			return
+		case <-ping.C:
+			if err := writeEvent(ws, models.RealtimeEvent{Type: constants.RealtimeEventPing}); err != nil {
+				return
+			}
+		case payload := <-client.Events():
+			if err := writeFrame(ws, payload); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// replay writes the messages the client missed since lastMessageID, or asks it to refetch
+// over HTTP when there are too many or the position is unknown
+func (h *RealtimeHandler) replay(ws *websocket.Conn, userID, lastMessageID uuid.UUID) {
+	messages, complete, err := h.messageService.MessagesSince(userID, lastMessageID, constants.RealtimeResumeLimit)
+	if err != nil && !errors.Is(err, apperrors.ErrMessageNotFound) {
+		log.Printf("realtime: resume for %s: %v", userID, err)
+	}
+
+	for i := range messages {
+		event := models.RealtimeEvent{
+			Type:           constants.RealtimeEventMessage,
+			ConversationID: &messages[i].ConversationID,
+			Data:           &messages[i],
+		}
+		if err := writeEvent(ws, event); err != nil {
+			return
+		}
+	}
+	if err != nil || !complete {
+		writeEvent(ws, models.RealtimeEvent{Type: constants.RealtimeEventResync})
+	}
+}
+
+// readEvents handles typing indicators and read receipts sent by the client until the
+// connection closes or stays silent for RealtimeReadTimeout
+func (h *RealtimeHandler) readEvents(ws *websocket.Conn, client *services.RealtimeClient) {
+	for {
+		ws.SetReadDeadline(time.Now().Add(constants.RealtimeReadTimeout))
+
+		var event models.ClientEvent
+		if err := websocket.JSON.Receive(ws, &event); err != nil {
+			// A malformed event leaves the connection usable
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				client.Send(errorEvent(apperrors.ErrInvalidInput))
+				continue
+			}
+			return
+		}
+
+		var err error
+		switch event.Type {
+		case constants.RealtimeEventPong:
+			// Receiving it extended the read deadline
+		case constants.RealtimeEventTyping:
+			err = h.conversationService.SetTyping(client.UserID, event.ConversationID, event.Typing)
+		case constants.RealtimeEventRead:
+			err = h.conversationService.MarkRead(client.UserID, event.ConversationID, event.MessageID)
+		default:
+			err = apperrors.ErrInvalidInput
+		}
+		if err != nil {
+			client.Send(errorEvent(err))
+		}
+	}
+}
+
+func writeEvent(ws *websocket.Conn, event models.RealtimeEvent) error {
+	ws.SetWriteDeadline(time.Now().Add(constants.RealtimeWriteTimeout))
+	return websocket.JSON.Send(ws, event)
+}
+
+func writeFrame(ws *websocket.Conn, payload []byte) error {
+	ws.SetWriteDeadline(time.Now().Add(constants.RealtimeWriteTimeout))
+	return websocket.Message.Send(ws, string(payload))
+}
+
+// errorEvent reports a failed client event without exposing internal errors
+func errorEvent(err error) models.RealtimeEvent {
+	message := apperrors.ErrInternalServer.Error()
+	if errors.Is(err, apperrors.ErrInvalidInput) ||
+		errors.Is(err, apperrors.ErrConversationNotFound) ||
+		errors.Is(err, apperrors.ErrMessageNotFound) {
+		message = err.Error()
+	}
+	return models.RealtimeEvent{Type: constants.RealtimeEventError, Data: gin.H{"message": message}}
+}
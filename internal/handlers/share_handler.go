@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/services"
+	"social-media-backend/internal/utils"
+)
+
+type ShareHandler struct {
+	shareService *services.ShareService
+}
+
+func NewShareHandler(shareService *services.ShareService) *ShareHandler {
+	return &ShareHandler{shareService: shareService}
+}
+
+// Repost handles POST /posts/:id/repost
+func (h *ShareHandler) Repost(c *gin.Context) {
+	postID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	post, err := h.shareService.Repost(currentUserID(c), postID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Post reposted", post)
+}
+
+// UndoRepost handles DELETE /posts/:id/repost
+func (h *ShareHandler) UndoRepost(c *gin.Context) {
+	postID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.shareService.UndoRepost(currentUserID(c), postID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Repost removed", nil)
+}
+
+// QuotePost handles POST /posts/:id/quote
+func (h *ShareHandler) QuotePost(c *gin.Context) {
+	postID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.QuotePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	post, err := h.shareService.QuotePost(currentUserID(c), postID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Post shared", post)
+}
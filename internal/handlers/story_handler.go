@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/services"
+	"social-media-backend/internal/utils"
+)
+
+type StoryHandler struct {
+	storyService *services.StoryService
+}
+
+func NewStoryHandler(storyService *services.StoryService) *StoryHandler {
+	return &StoryHandler{storyService: storyService}
+}
+
+// CreateStory handles POST /stories, a multipart form with the media file in "media" and
+// optional stickers as a JSON array in "stickers"
+func (h *StoryHandler) CreateStory(c *gin.Context) {
+	var req models.CreateStoryRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if raw := c.PostForm("stickers"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &req.Stickers); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "invalid stickers")
+			return
+		}
+		if err := binding.Validator.ValidateStruct(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	file, err := c.FormFile("media")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "media file is required")
+		return
+	}
+
+	story, err := h.storyService.CreateStory(c.Request.Context(), currentUserID(c), &req, file)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Story created successfully", story)
+}
+
+// GetStoryTray handles GET /stories/tray
+func (h *StoryHandler) GetStoryTray(c *gin.Context) {
+	tray, err := h.storyService.GetStoryTray(currentUserID(c))
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", tray)
+}
+
+// GetStory handles GET /stories/:id
+func (h *StoryHandler) GetStory(c *gin.Context) {
+	storyID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	story, err := h.storyService.GetStory(currentUserID(c), storyID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", story)
+}
+
+// RecordView handles POST /stories/:id/views
+func (h *StoryHandler) RecordView(c *gin.Context) {
+	storyID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.storyService.RecordView(currentUserID(c), storyID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Story viewed", nil)
+}
+
+// GetStoryViewers handles GET /stories/:id/views
+func (h *StoryHandler) GetStoryViewers(c *gin.Context) {
+	storyID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.storyService.GetStoryViewers(currentUserID(c), storyID, cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
+
+// ReplyToStory handles POST /stories/:id/replies
+func (h *StoryHandler) ReplyToStory(c *gin.Context) {
+	storyID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.StoryReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	message, err := h.storyService.ReplyToStory(currentUserID(c), storyID, req.Content)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Reply sent", message)
+}
+
+// ReactToStory handles POST /stories/:id/reactions
+func (h *StoryHandler) ReactToStory(c *gin.Context) {
+	storyID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.StoryReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	message, err := h.storyService.ReactToStory(currentUserID(c), storyID, req.Emoji)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Reaction sent", message)
+}
+
+// AnswerSticker handles POST /stories/:id/stickers/:sticker_id/answers
+func (h *StoryHandler) AnswerSticker(c *gin.Context) {
+	storyID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	stickerID, ok := parseUUIDParam(c, "sticker_id")
+	if !ok {
+		return
+	}
+
+	var req models.StickerAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sticker, err := h.storyService.AnswerSticker(currentUserID(c), storyID, stickerID, &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Answer recorded", sticker)
+}
+
+// GetStickerAnswers handles GET /stories/:id/stickers/:sticker_id/answers
+func (h *StoryHandler) GetStickerAnswers(c *gin.Context) {
+	storyID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	stickerID, ok := parseUUIDParam(c, "sticker_id")
+	if !ok {
+		return
+	}
+
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.storyService.GetStickerAnswers(currentUserID(c), storyID, stickerID, cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
+
+// DeleteStory handles DELETE /stories/:id
+func (h *StoryHandler) DeleteStory(c *gin.Context) {
+	storyID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.storyService.DeleteStory(c.Request.Context(), currentUserID(c), storyID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Story deleted successfully", nil)
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/services"
+	"social-media-backend/internal/utils"
+)
+
+type UserHandler struct {
+	userService    *services.UserService
+	blockService   *services.BlockService
+	muteService    *services.MuteService
+	mentionService *services.MentionService
+	followService  *services.FollowService
+}
+
+func NewUserHandler(userService *services.UserService, blockService *services.BlockService, muteService *services.MuteService, mentionService *services.MentionService, followService *services.FollowService) *UserHandler {
+	return &UserHandler{userService: userService, blockService: blockService, muteService: muteService, mentionService: mentionService, followService: followService}
+}
+
+// GetSettings handles GET /users/me/settings
+func (h *UserHandler) GetSettings(c *gin.Context) {
+	settings, err := h.userService.GetSettings(currentUserID(c))
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", settings)
+}
+
+// UpdateSettings handles PATCH /users/me/settings
+func (h *UserHandler) UpdateSettings(c *gin.Context) {
+	var req models.UpdateSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	settings, err := h.userService.UpdateSettings(currentUserID(c), &req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Settings updated", settings)
+}
+
+// BlockUser handles POST /users/:id/block
+func (h *UserHandler) BlockUser(c *gin.Context) {
+	userID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.blockService.BlockUser(currentUserID(c), userID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User blocked", nil)
+}
+
+// UnblockUser handles DELETE /users/:id/block
+func (h *UserHandler) UnblockUser(c *gin.Context) {
+	userID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.blockService.UnblockUser(currentUserID(c), userID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User unblocked", nil)
+}
+
+// GetBlockedUsers handles GET /users/me/blocks
+func (h *UserHandler) GetBlockedUsers(c *gin.Context) {
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.blockService.GetBlockedUsers(currentUserID(c), cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
+
+// MuteUser handles POST /users/:id/mute
+func (h *UserHandler) MuteUser(c *gin.Context) {
+	userID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.muteService.MuteUser(currentUserID(c), userID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User muted", nil)
+}
+
+// UnmuteUser handles DELETE /users/:id/mute
+func (h *UserHandler) UnmuteUser(c *gin.Context) {
+	userID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.muteService.UnmuteUser(currentUserID(c), userID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User unmuted", nil)
+}
+
+// GetMutedUsers handles GET /users/me/mutes
+func (h *UserHandler) GetMutedUsers(c *gin.Context) {
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.muteService.GetMutedUsers(currentUserID(c), cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
+
+// GetMentionedPosts handles GET /users/:id/mentions
+func (h *UserHandler) GetMentionedPosts(c *gin.Context) {
+	userID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.mentionService.GetMentionedPosts(currentUserID(c), userID, cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
+
+// GetFollowers handles GET /users/:id/followers
+func (h *UserHandler) GetFollowers(c *gin.Context) {
+	userID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.followService.GetFollowers(currentUserID(c), userID, cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
+
+// GetFollowing handles GET /users/:id/following
+func (h *UserHandler) GetFollowing(c *gin.Context) {
+	userID, ok := parseUUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	cursor, limit, err := cursorParams(c)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	page, err := h.followService.GetFollowing(currentUserID(c), userID, cursor, limit)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", page)
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"social-media-backend/internal/utils"
+	apperrors "social-media-backend/pkg/errors"
+)
+
+const (
+	ContextUserID = "user_id"
+	ContextClaims = "claims"
+)
+
+// AuthMiddleware rejects requests without a valid bearer token
+func AuthMiddleware(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := claimsFromRequest(c, secret)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		c.Set(ContextUserID, claims.UserID)
+		c.Set(ContextClaims, claims)
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware sets the user when a valid token is present but lets anonymous requests through
+func OptionalAuthMiddleware(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims, err := claimsFromRequest(c, secret); err == nil {
+			c.Set(ContextUserID, claims.UserID)
+			c.Set(ContextClaims, claims)
+		}
+		c.Next()
+	}
+}
+
+// WebSocketAuthMiddleware is AuthMiddleware for WebSocket upgrades. Browsers cannot set
+// headers on WebSocket requests, so the token may also come in the access_token query parameter.
+func WebSocketAuthMiddleware(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := claimsFromRequest(c, secret)
+		if errors.Is(err, apperrors.ErrUnauthorized) {
+			if token := c.Query("access_token"); token != "" {
+				claims, err = utils.ValidateToken(token, secret)
+				if err != nil {
+					err = apperrors.ErrInvalidToken
+				}
+			}
+		}
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		c.Set(ContextUserID, claims.UserID)
+		c.Set(ContextClaims, claims)
+		c.Next()
+	}
+}
+
+func claimsFromRequest(c *gin.Context, secret string) (*utils.Claims, error) {
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		return nil, apperrors.ErrUnauthorized
+	}
+
+	parts := strings.SplitN(header, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	claims, err := utils.ValidateToken(parts[1], secret)
+	if err != nil {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Block stops two users from interacting. It applies in both directions.
+type Block struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BlockerID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_blocks_blocker_blocked" json:"blocker_id"`
+	BlockedID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_blocks_blocker_blocked;index" json:"blocked_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationships
+	Blocked User `gorm:"foreignKey:BlockedID" json:"blocked,omitempty"`
+}
+
+func (b *Block) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
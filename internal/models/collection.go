@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SavedPost is a bookmark of a post by a user
+type SavedPost struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_saved_posts_user_post" json:"user_id"`
+	PostID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_saved_posts_user_post;index" json:"post_id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Post Post `gorm:"foreignKey:PostID" json:"post,omitempty"`
+}
+
+func (sp *SavedPost) BeforeCreate(tx *gorm.DB) error {
+	if sp.ID == uuid.Nil {
+		sp.ID = uuid.New()
+	}
+	return nil
+}
+
+// Collection is a named group of saved posts
+type Collection struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_collections_user_name" json:"user_id"`
+	Name      string    `gorm:"not null;size:100;uniqueIndex:idx_collections_user_name" json:"name"`
+	Position  int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	User  User             `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Items []CollectionItem `gorm:"foreignKey:CollectionID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+}
+
+func (c *Collection) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// CollectionItem places a saved post into a collection. A post can be in several collections.
+type CollectionItem struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CollectionID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_collection_items_collection_post" json:"collection_id"`
+	PostID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_collection_items_collection_post;index" json:"post_id"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Relationships
+	Collection Collection `gorm:"foreignKey:CollectionID" json:"collection,omitempty"`
+	Post       Post       `gorm:"foreignKey:PostID" json:"post,omitempty"`
+}
+
+func (ci *CollectionItem) BeforeCreate(tx *gorm.DB) error {
+	if ci.ID == uuid.Nil {
+		ci.ID = uuid.New()
+	}
+	return nil
+}
+
+// SavePostRequest for saving a post, optionally into collections
+type SavePostRequest struct {
+	CollectionIDs []uuid.UUID `json:"collection_ids,omitempty"`
+}
+
+// CreateCollectionRequest for creating a collection
+type CreateCollectionRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+// UpdateCollectionRequest for renaming a collection
+type UpdateCollectionRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+// ReorderCollectionsRequest lists every collection of the user in the desired order
+type ReorderCollectionsRequest struct {
+	CollectionIDs []uuid.UUID `json:"collection_ids" binding:"required,min=1,dive,required"`
+}
+
+// CollectionResponse for collection data
+type CollectionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	Position   int       `json:"position"`
+	PostsCount int       `json:"posts_count"`
+	CoverURL   string    `json:"cover_url,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
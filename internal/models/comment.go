@@ -8,14 +8,16 @@ import (
 )
 
 type Comment struct {
-	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	PostID     uuid.UUID `gorm:"type:uuid;not null;index" json:"post_id"`
-	UserID     uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
-	ParentID   *uuid.UUID `gorm:"type:uuid;index" json:"parent_id,omitempty"` // For nested comments
-	Content    string    `gorm:"type:text;not null" json:"content"`
-	LikesCount int       `gorm:"default:0" json:"likes_count"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	PostID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"post_id"`
+	UserID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	ParentID   *uuid.UUID     `gorm:"type:uuid;index" json:"parent_id,omitempty"`             // For nested comments
+	Depth      int            `gorm:"not null;default:0" json:"depth"`                        // 0 for top level comments
+	Status     string         `gorm:"size:20;not null;default:'visible';index" json:"status"` // visible, hidden, pending_review
+	Content    string         `gorm:"type:text;not null" json:"content"`
+	LikesCount int            `gorm:"default:0" json:"likes_count"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
 	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationships
@@ -51,14 +53,57 @@ type UpdateCommentRequest struct {
 
 // CommentResponse includes user info
 type CommentResponse struct {
-	ID           uuid.UUID    `json:"id"`
-	PostID       uuid.UUID    `json:"post_id"`
-	User         UserResponse `json:"user"`
-	ParentID     *uuid.UUID   `json:"parent_id,omitempty"`
-	Content      string       `json:"content"`
-	LikesCount   int          `json:"likes_count"`
-	RepliesCount int          `json:"replies_count"`
-	IsLiked      bool         `json:"is_liked"`
-	CreatedAt    time.Time    `json:"created_at"`
-	UpdatedAt    time.Time    `json:"updated_at"`
-}
\ No newline at end of file
+	ID             uuid.UUID         `json:"id"`
+	PostID         uuid.UUID         `json:"post_id"`
+	User           UserResponse      `json:"user"`
+	ParentID       *uuid.UUID        `json:"parent_id,omitempty"`
+	Depth          int               `json:"depth"`
+	Status         string            `json:"status,omitempty"` // Only set for the comment and post authors
+	IsPinned       bool              `json:"is_pinned"`
+	Content        string            `json:"content"`
+	LikesCount     int               `json:"likes_count"`
+	RepliesCount   int               `json:"replies_count"`
+	IsLiked        bool              `json:"is_liked"`
+	ReactionCounts map[string]int    `json:"reaction_counts,omitempty"`
+	ViewerReaction string            `json:"viewer_reaction,omitempty"`
+	Mentions       []MentionResponse `json:"mentions,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// ToResponse converts a comment to its public representation
+func (c *Comment) ToResponse() CommentResponse {
+	return CommentResponse{
+		ID:           c.ID,
+		PostID:       c.PostID,
+		User:         c.User.ToResponse(),
+		ParentID:     c.ParentID,
+		Depth:        c.Depth,
+		Content:      c.Content,
+		LikesCount:   c.LikesCount,
+		RepliesCount: c.RepliesCount,
+		IsLiked:      c.IsLiked,
+		CreatedAt:    c.CreatedAt,
+		UpdatedAt:    c.UpdatedAt,
+	}
+}
+
+// CommentFilterKeyword auto-hides comments containing the keyword on the user's posts
+type CommentFilterKeyword struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_comment_filter_keywords_user_keyword" json:"user_id"`
+	Keyword   string    `gorm:"not null;size:100;uniqueIndex:idx_comment_filter_keywords_user_keyword" json:"keyword"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (k *CommentFilterKeyword) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return nil
+}
+
+// AddFilterKeywordRequest for adding a comment filter keyword
+type AddFilterKeywordRequest struct {
+	Keyword string `json:"keyword" binding:"required,min=1,max=100"`
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Conversation holds the messages between its participants. Direct conversations have
+// exactly two participants and are unique per pair of users; groups have a name and admins.
+type Conversation struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Kind        string     `gorm:"size:20;not null;default:'direct'" json:"kind"` // direct, group
+	DirectKey   *string    `gorm:"size:73;uniqueIndex" json:"-"`                  // Sorted user IDs of a direct conversation
+	Name        string     `gorm:"size:100" json:"name,omitempty"`
+	AvatarURL   string     `gorm:"size:255" json:"avatar_url,omitempty"`
+	AvatarKey   string     `gorm:"size:255" json:"-"` // Storage key of the uploaded avatar
+	CreatedByID *uuid.UUID `gorm:"type:uuid" json:"created_by_id,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Relationships
+	Participants []ConversationParticipant `gorm:"foreignKey:ConversationID;constraint:OnDelete:CASCADE" json:"participants,omitempty"`
+}
+
+func (c *Conversation) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// ConversationParticipant is a user's membership in a conversation. Participants see the
+// messages sent since they joined; their read cursor points at the last message they read.
+// Conversations started by someone the user does not follow stay pending in their message
+// requests until they accept.
+type ConversationParticipant struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ConversationID    uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_conversation_participants_conversation_user" json:"conversation_id"`
+	UserID            uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_conversation_participants_conversation_user;index" json:"user_id"`
+	Role              string     `gorm:"size:20;not null;default:'member'" json:"role"`     // admin, member
+	Status            string     `gorm:"size:20;not null;default:'accepted'" json:"status"` // accepted, pending
+	AddedByID         *uuid.UUID `gorm:"type:uuid" json:"added_by_id,omitempty"`            // Who brought the user into the conversation
+	LastReadMessageID *uuid.UUID `gorm:"type:uuid" json:"last_read_message_id,omitempty"`
+	LastReadMessageAt *time.Time `json:"-"`                      // Creation time of the last read message, the unread boundary
+	LastReadAt        *time.Time `json:"last_read_at,omitempty"` // When the participant read up to the cursor
+	JoinedAt          time.Time  `gorm:"not null" json:"joined_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (p *ConversationParticipant) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	if p.JoinedAt.IsZero() {
+		p.JoinedAt = time.Now()
+	}
+	return nil
+}
+
+// CreateConversationRequest starts a conversation. A single user without a name opens the
+// direct conversation with them; anything else creates a group.
+type CreateConversationRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids" binding:"required,min=1,max=249,dive,required"`
+	Name    string      `json:"name,omitempty" binding:"max=100"`
+}
+
+// UpdateConversationRequest for renaming a group
+type UpdateConversationRequest struct {
+	Name *string `json:"name,omitempty" binding:"omitempty,max=100"`
+}
+
+// AddParticipantsRequest for adding users to a group
+type AddParticipantsRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids" binding:"required,min=1,max=249,dive,required"`
+}
+
+// UpdateParticipantRoleRequest for promoting a participant to admin or demoting them
+type UpdateParticipantRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin member"`
+}
+
+// MarkReadRequest moves the read cursor to a message, or to the latest message when omitted
+type MarkReadRequest struct {
+	MessageID *uuid.UUID `json:"message_id,omitempty"`
+}
+
+// ConversationResponse for conversation data. User is the other participant of a direct
+// conversation; Participants are only included for a single conversation. Status is pending
+// while the conversation is in the viewer's message requests.
+type ConversationResponse struct {
+	ID                uuid.UUID             `json:"id"`
+	Kind              string                `json:"kind"`
+	Name              string                `json:"name,omitempty"`
+	AvatarURL         string                `json:"avatar_url,omitempty"`
+	User              *UserResponse         `json:"user,omitempty"`
+	Role              string                `json:"role"`
+	Status            string                `json:"status"`
+	ParticipantsCount int                   `json:"participants_count"`
+	Participants      []ParticipantResponse `json:"participants,omitempty"`
+	LastMessage       *MessageResponse      `json:"last_message,omitempty"`
+	UnreadCount       int                   `json:"unread_count"`
+	CreatedAt         time.Time             `json:"created_at"`
+	UpdatedAt         time.Time             `json:"updated_at"`
+}
+
+// ParticipantResponse for a conversation member and how far they have read
+type ParticipantResponse struct {
+	User              UserResponse `json:"user"`
+	Role              string       `json:"role"`
+	LastReadMessageID *uuid.UUID   `json:"last_read_message_id,omitempty"`
+	LastReadAt        *time.Time   `json:"last_read_at,omitempty"`
+	JoinedAt          time.Time    `json:"joined_at"`
+}
+
+func (p *ConversationParticipant) ToResponse() ParticipantResponse {
+	return ParticipantResponse{
+		User:              p.User.ToResponse(),
+		Role:              p.Role,
+		LastReadMessageID: p.LastReadMessageID,
+		LastReadAt:        p.LastReadAt,
+		JoinedAt:          p.JoinedAt,
+	}
+}
@@ -25,10 +25,59 @@ func (h *Hashtag) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// ToResponse converts a hashtag to its public representation
+func (h *Hashtag) ToResponse() HashtagResponse {
+	return HashtagResponse{
+		ID:        h.ID,
+		Name:      h.Name,
+		PostCount: h.PostCount,
+		CreatedAt: h.CreatedAt,
+	}
+}
+
 // HashtagResponse for hashtag data
 type HashtagResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	PostCount int       `json:"post_count"`
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	PostCount   int       `json:"post_count"`
+	IsFollowing bool      `json:"is_following"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// HashtagFollow subscribes a user to posts tagged with a hashtag
+type HashtagFollow struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_hashtag_follows_user_hashtag" json:"user_id"`
+	HashtagID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_hashtag_follows_user_hashtag;index" json:"hashtag_id"`
 	CreatedAt time.Time `json:"created_at"`
-}
\ No newline at end of file
+
+	// Relationships
+	Hashtag Hashtag `gorm:"foreignKey:HashtagID" json:"hashtag,omitempty"`
+}
+
+func (f *HashtagFollow) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return nil
+}
+
+// HashtagUsage counts the posts tagged with a hashtag during one hour
+type HashtagUsage struct {
+	HashtagID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"hashtag_id"`
+	BucketStart time.Time `gorm:"primaryKey;index" json:"bucket_start"`
+	Count       int       `gorm:"not null;default:0" json:"count"`
+}
+
+// TrendingHashtagResponse is a hashtag ranked by recent usage
+type TrendingHashtagResponse struct {
+	HashtagResponse
+	Uses  int     `json:"uses"`  // Posts tagged within the window
+	Score float64 `json:"score"` // Decayed velocity relative to the hashtag's baseline
+}
+
+// HashtagUsagePoint is one hourly bucket of a hashtag's usage time series
+type HashtagUsagePoint struct {
+	BucketStart time.Time `json:"bucket_start"`
+	Count       int       `json:"count"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Highlight is a named group of stories shown on the user's profile after they expire
+type Highlight struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Title        string     `gorm:"not null;size:50" json:"title"`
+	CoverURL     string     `gorm:"size:255" json:"cover_url,omitempty"` // Uploaded cover image
+	CoverKey     string     `gorm:"size:255" json:"-"`                   // Storage key of the uploaded cover
+	CoverStoryID *uuid.UUID `gorm:"type:uuid" json:"cover_story_id,omitempty"`
+	Position     int        `gorm:"not null;default:0" json:"position"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Relationships
+	User  User            `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Items []HighlightItem `gorm:"foreignKey:HighlightID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+}
+
+func (h *Highlight) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == uuid.Nil {
+		h.ID = uuid.New()
+	}
+	return nil
+}
+
+// HighlightItem places a story into a highlight. The story media is kept as long as the
+// story is in at least one highlight.
+type HighlightItem struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	HighlightID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_highlight_items_highlight_story" json:"highlight_id"`
+	StoryID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_highlight_items_highlight_story;index" json:"story_id"`
+	Position    int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Relationships
+	Highlight Highlight `gorm:"foreignKey:HighlightID" json:"highlight,omitempty"`
+	Story     Story     `gorm:"foreignKey:StoryID" json:"story,omitempty"`
+}
+
+func (hi *HighlightItem) BeforeCreate(tx *gorm.DB) error {
+	if hi.ID == uuid.Nil {
+		hi.ID = uuid.New()
+	}
+	return nil
+}
+
+// CreateHighlightRequest for creating a highlight from stories
+type CreateHighlightRequest struct {
+	Title    string      `json:"title" binding:"required,min=1,max=50"`
+	StoryIDs []uuid.UUID `json:"story_ids" binding:"required,min=1,dive,required"`
+}
+
+// UpdateHighlightRequest for renaming a highlight or using one of its stories as the cover
+type UpdateHighlightRequest struct {
+	Title        *string    `json:"title,omitempty" binding:"omitempty,min=1,max=50"`
+	CoverStoryID *uuid.UUID `json:"cover_story_id,omitempty"`
+}
+
+// HighlightStoriesRequest lists stories to add to a highlight, or all of its stories in
+// the desired order when reordering
+type HighlightStoriesRequest struct {
+	StoryIDs []uuid.UUID `json:"story_ids" binding:"required,min=1,dive,required"`
+}
+
+// HighlightResponse for highlight data. Stories are only included for a single highlight.
+type HighlightResponse struct {
+	ID           uuid.UUID       `json:"id"`
+	Title        string          `json:"title"`
+	CoverURL     string          `json:"cover_url,omitempty"`
+	Position     int             `json:"position"`
+	StoriesCount int             `json:"stories_count"`
+	Stories      []StoryResponse `json:"stories,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
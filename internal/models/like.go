@@ -7,17 +7,22 @@ import (
 	"gorm.io/gorm"
 )
 
+// Like is a typed reaction on exactly one post, comment or message. A user has at most one
+// reaction per target, enforced by partial unique indexes created in the migration.
 type Like struct {
 	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
 	PostID    *uuid.UUID `gorm:"type:uuid;index" json:"post_id,omitempty"`
 	CommentID *uuid.UUID `gorm:"type:uuid;index" json:"comment_id,omitempty"`
+	MessageID *uuid.UUID `gorm:"type:uuid;index" json:"message_id,omitempty"`
+	Type      string     `gorm:"not null;size:20;default:'like'" json:"type"` // like, love, laugh, wow, sad, angry
 	CreatedAt time.Time  `json:"created_at"`
 
 	// Relationships
 	User    User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
 	Post    *Post    `gorm:"foreignKey:PostID" json:"post,omitempty"`
 	Comment *Comment `gorm:"foreignKey:CommentID" json:"comment,omitempty"`
+	Message *Message `gorm:"foreignKey:MessageID" json:"message,omitempty"`
 }
 
 func (l *Like) BeforeCreate(tx *gorm.DB) error {
@@ -27,8 +32,27 @@ func (l *Like) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
-// LikeRequest for liking posts or comments
+// LikeRequest for liking posts or comments. Exactly one target must be set.
 type LikeRequest struct {
 	PostID    *uuid.UUID `json:"post_id,omitempty"`
 	CommentID *uuid.UUID `json:"comment_id,omitempty"`
-}
\ No newline at end of file
+}
+
+// LikeStatusResponse is returned by like and unlike
+type LikeStatusResponse struct {
+	Liked      bool `json:"liked"`
+	LikesCount int  `json:"likes_count"`
+}
+
+// ReactRequest for reacting to a post, comment or message
+type ReactRequest struct {
+	Type string `json:"type" binding:"required,oneof=like love laugh wow sad angry"`
+}
+
+// ReactionResponse for the list of who reacted
+type ReactionResponse struct {
+	ID        uuid.UUID    `json:"id"`
+	User      UserResponse `json:"user"`
+	Type      string       `json:"type"`
+	CreatedAt time.Time    `json:"created_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Mention links an @username in a post caption, comment or story caption to the user.
+// Exactly one of PostID, CommentID and StoryID is set.
+type Mention struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"` // Who is mentioned
+	ActorID   uuid.UUID  `gorm:"type:uuid;not null" json:"actor_id"`      // Who wrote the mention
+	PostID    *uuid.UUID `gorm:"type:uuid;index" json:"post_id,omitempty"`
+	CommentID *uuid.UUID `gorm:"type:uuid;index" json:"comment_id,omitempty"`
+	StoryID   *uuid.UUID `gorm:"type:uuid;index" json:"story_id,omitempty"`
+	Offset    int        `gorm:"column:char_offset;not null" json:"offset"` // In characters, including the '@'
+	Length    int        `gorm:"column:char_length;not null" json:"length"`
+	CreatedAt time.Time  `json:"created_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (m *Mention) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// MentionResponse locates a mention in its text for client-side linking
+type MentionResponse struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Offset   int       `json:"offset"`
+	Length   int       `json:"length"`
+}
@@ -7,22 +7,25 @@ import (
 	"gorm.io/gorm"
 )
 
+// Message is sent by a participant to a conversation. System messages record membership
+// and group changes, with the acting user as sender.
 type Message struct {
-	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	SenderID   uuid.UUID `gorm:"type:uuid;not null;index" json:"sender_id"`
-	ReceiverID uuid.UUID `gorm:"type:uuid;not null;index" json:"receiver_id"`
-	Content    string    `gorm:"type:text;not null" json:"content"`
-	MediaURL   string    `gorm:"size:255" json:"media_url,omitempty"`
-	MediaType  string    `gorm:"size:20" json:"media_type,omitempty"` // image, video
-	IsRead     bool      `gorm:"default:false" json:"is_read"`
-	ReadAt     *time.Time `json:"read_at,omitempty"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	ID             uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ConversationID uuid.UUID      `gorm:"type:uuid;not null;index" json:"conversation_id"`
+	SenderID       uuid.UUID      `gorm:"type:uuid;not null;index" json:"sender_id"`
+	Kind           string         `gorm:"size:20;not null;default:'text'" json:"kind"` // text, story_reply, story_reaction, system
+	StoryID        *uuid.UUID     `gorm:"type:uuid;index" json:"story_id,omitempty"`   // Story replied or reacted to, kept after the story is gone
+	Content        string         `gorm:"type:text;not null" json:"content"`
+	MediaURL       string         `gorm:"size:255" json:"media_url,omitempty"`
+	MediaKey       string         `gorm:"size:255" json:"-"`                   // Storage key of the uploaded media
+	MediaType      string         `gorm:"size:20" json:"media_type,omitempty"` // image, video
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationships
-	Sender   User `gorm:"foreignKey:SenderID" json:"sender,omitempty"`
-	Receiver User `gorm:"foreignKey:ReceiverID" json:"receiver,omitempty"`
+	Conversation Conversation `gorm:"foreignKey:ConversationID" json:"conversation,omitempty"`
+	Sender       User         `gorm:"foreignKey:SenderID" json:"sender,omitempty"`
 }
 
 func (m *Message) BeforeCreate(tx *gorm.DB) error {
@@ -32,29 +35,82 @@ func (m *Message) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
-// SendMessageRequest for sending messages
+// HiddenMessage removes a message from one participant's view of the conversation
+type HiddenMessage struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	MessageID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_hidden_messages_message_user" json:"message_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_hidden_messages_message_user;index" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationships
+	Message Message `gorm:"foreignKey:MessageID;constraint:OnDelete:CASCADE" json:"message,omitempty"`
+}
+
+func (h *HiddenMessage) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == uuid.Nil {
+		h.ID = uuid.New()
+	}
+	return nil
+}
+
+// SendMessageRequest for sending a direct message to a user, starting the direct
+// conversation if needed. Sent as JSON, or as a multipart form with the media file in "media".
 type SendMessageRequest struct {
-	ReceiverID uuid.UUID `json:"receiver_id" binding:"required"`
-	Content    string    `json:"content" binding:"required,min=1,max=5000"`
-	MediaType  string    `json:"media_type,omitempty" binding:"omitempty,oneof=image video"`
+	ReceiverID uuid.UUID `json:"receiver_id" form:"receiver_id" binding:"required"`
+	Content    string    `json:"content" form:"content" binding:"max=5000"`
+	MediaType  string    `json:"media_type,omitempty" form:"media_type" binding:"omitempty,oneof=image video"`
+}
+
+// ConversationMessageRequest for sending a message to an existing conversation
+type ConversationMessageRequest struct {
+	Content   string `json:"content" form:"content" binding:"max=5000"`
+	MediaType string `json:"media_type,omitempty" form:"media_type" binding:"omitempty,oneof=image video"`
 }
 
-// MessageResponse includes sender and receiver info
+// MessageResponse includes sender info. Read state is tracked per participant on the
+// conversation.
 type MessageResponse struct {
-	ID         uuid.UUID    `json:"id"`
-	Sender     UserResponse `json:"sender"`
-	Receiver   UserResponse `json:"receiver"`
-	Content    string       `json:"content"`
-	MediaURL   string       `json:"media_url,omitempty"`
-	MediaType  string       `json:"media_type,omitempty"`
-	IsRead     bool         `json:"is_read"`
-	ReadAt     *time.Time   `json:"read_at,omitempty"`
-	CreatedAt  time.Time    `json:"created_at"`
-}
-
-// Conversation represents a conversation between two users
-type Conversation struct {
-	User          UserResponse `json:"user"`
-	LastMessage   MessageResponse `json:"last_message"`
-	UnreadCount   int          `json:"unread_count"`
-}
\ No newline at end of file
+	ID             uuid.UUID             `json:"id"`
+	ConversationID uuid.UUID             `json:"conversation_id"`
+	Sender         UserResponse          `json:"sender"`
+	Kind           string                `json:"kind"`
+	Content        string                `json:"content"`
+	Story          *MessageStoryResponse `json:"story,omitempty"`
+	MediaURL       string                `json:"media_url,omitempty"`
+	MediaType      string                `json:"media_type,omitempty"`
+	ReactionCounts map[string]int        `json:"reaction_counts,omitempty"`
+	ViewerReaction string                `json:"viewer_reaction,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+}
+
+func (m *Message) ToResponse() MessageResponse {
+	return MessageResponse{
+		ID:             m.ID,
+		ConversationID: m.ConversationID,
+		Sender:         m.Sender.ToResponse(),
+		Kind:           m.Kind,
+		Content:        m.Content,
+		MediaURL:       m.MediaURL,
+		MediaType:      m.MediaType,
+		CreatedAt:      m.CreatedAt,
+	}
+}
+
+// MessageStoryResponse is the story a message replies or reacts to. The media is only
+// included while the story is live; afterwards clients show the story as unavailable.
+type MessageStoryResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Available bool      `json:"available"`
+	MediaURL  string    `json:"media_url,omitempty"`
+	MediaType string    `json:"media_type,omitempty"`
+}
+
+// StoryReplyRequest for replying to a story with a direct message
+type StoryReplyRequest struct {
+	Content string `json:"content" binding:"required,min=1,max=5000"`
+}
+
+// StoryReactionRequest for reacting to a story with one of the quick reaction emojis
+type StoryReactionRequest struct {
+	Emoji string `json:"emoji" binding:"required"`
+}
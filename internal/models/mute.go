@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Mute hides a user's posts from the muter's feeds and explore page without them knowing
+type Mute struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	MuterID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_mutes_muter_muted" json:"muter_id"`
+	MutedID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_mutes_muter_muted" json:"muted_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationships
+	Muted User `gorm:"foreignKey:MutedID" json:"muted,omitempty"`
+}
+
+func (m *Mute) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
@@ -10,10 +10,11 @@ import (
 type Notification struct {
 	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"` // Who receives the notification
-	ActorID   uuid.UUID  `gorm:"type:uuid;not null" json:"actor_id"` // Who triggered the notification
-	Type      string     `gorm:"not null;size:50" json:"type"` // like, comment, follow, mention
+	ActorID   uuid.UUID  `gorm:"type:uuid;not null" json:"actor_id"`      // Who triggered the notification
+	Type      string     `gorm:"not null;size:50" json:"type"`            // like, comment, follow, mention
 	PostID    *uuid.UUID `gorm:"type:uuid" json:"post_id,omitempty"`
 	CommentID *uuid.UUID `gorm:"type:uuid" json:"comment_id,omitempty"`
+	StoryID   *uuid.UUID `gorm:"type:uuid" json:"story_id,omitempty"`
 	Content   string     `gorm:"type:text" json:"content,omitempty"`
 	IsRead    bool       `gorm:"default:false" json:"is_read"`
 	CreatedAt time.Time  `json:"created_at"`
@@ -40,7 +41,22 @@ type NotificationResponse struct {
 	Type      string       `json:"type"`
 	PostID    *uuid.UUID   `json:"post_id,omitempty"`
 	CommentID *uuid.UUID   `json:"comment_id,omitempty"`
+	StoryID   *uuid.UUID   `json:"story_id,omitempty"`
 	Content   string       `json:"content,omitempty"`
 	IsRead    bool         `json:"is_read"`
 	CreatedAt time.Time    `json:"created_at"`
-}
\ No newline at end of file
+}
+
+func (n *Notification) ToResponse() NotificationResponse {
+	return NotificationResponse{
+		ID:        n.ID,
+		Actor:     n.Actor.ToResponse(),
+		Type:      n.Type,
+		PostID:    n.PostID,
+		CommentID: n.CommentID,
+		StoryID:   n.StoryID,
+		Content:   n.Content,
+		IsRead:    n.IsRead,
+		CreatedAt: n.CreatedAt,
+	}
+}
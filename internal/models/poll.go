@@ -0,0 +1,114 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Poll is attached to a post and closes at a fixed time
+type Poll struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	PostID         uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"post_id"`
+	AllowsMultiple bool       `gorm:"default:false" json:"allows_multiple"`
+	VotersCount    int        `gorm:"default:0" json:"voters_count"`
+	ClosesAt       time.Time  `gorm:"not null;index" json:"closes_at"`
+	NotifiedAt     *time.Time `json:"-"` // Set once the author was told the poll closed
+	CreatedAt      time.Time  `json:"created_at"`
+
+	// Relationships
+	Post    Post         `gorm:"foreignKey:PostID" json:"-"`
+	Options []PollOption `gorm:"foreignKey:PollID" json:"options,omitempty"`
+}
+
+func (p *Poll) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+func (p *Poll) IsClosed() bool {
+	return !time.Now().Before(p.ClosesAt)
+}
+
+// PollOption is one of the 2-4 answers of a poll
+type PollOption struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	PollID     uuid.UUID `gorm:"type:uuid;not null;index" json:"poll_id"`
+	Text       string    `gorm:"not null;size:80" json:"text"`
+	Position   int       `gorm:"not null" json:"position"`
+	VotesCount int       `gorm:"default:0" json:"votes_count"`
+}
+
+func (po *PollOption) BeforeCreate(tx *gorm.DB) error {
+	if po.ID == uuid.Nil {
+		po.ID = uuid.New()
+	}
+	return nil
+}
+
+// PollBallot records that a user voted. The unique index enforces one vote per user per poll.
+type PollBallot struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	PollID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_poll_ballots_poll_user" json:"poll_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_poll_ballots_poll_user" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationships
+	Votes []PollVote `gorm:"foreignKey:BallotID" json:"votes,omitempty"`
+}
+
+func (pb *PollBallot) BeforeCreate(tx *gorm.DB) error {
+	if pb.ID == uuid.Nil {
+		pb.ID = uuid.New()
+	}
+	return nil
+}
+
+// PollVote is an option chosen on a ballot. Multi choice polls have several per ballot.
+type PollVote struct {
+	ID       uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BallotID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_poll_votes_ballot_option" json:"ballot_id"`
+	OptionID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_poll_votes_ballot_option;index" json:"option_id"`
+}
+
+func (pv *PollVote) BeforeCreate(tx *gorm.DB) error {
+	if pv.ID == uuid.Nil {
+		pv.ID = uuid.New()
+	}
+	return nil
+}
+
+// CreatePollRequest for attaching a poll to a new post
+type CreatePollRequest struct {
+	Options        []string  `json:"options" binding:"required,min=2,max=4,dive,required,max=80"`
+	AllowsMultiple bool      `json:"allows_multiple"`
+	ClosesAt       time.Time `json:"closes_at" binding:"required"`
+}
+
+// VotePollRequest for voting on a poll
+type VotePollRequest struct {
+	OptionIDs []uuid.UUID `json:"option_ids" binding:"required,min=1,max=4,dive,required"`
+}
+
+// PollResponse hides tallies until the viewer voted or the poll closed
+type PollResponse struct {
+	ID              uuid.UUID            `json:"id"`
+	AllowsMultiple  bool                 `json:"allows_multiple"`
+	VotersCount     *int                 `json:"voters_count,omitempty"`
+	ClosesAt        time.Time            `json:"closes_at"`
+	IsClosed        bool                 `json:"is_closed"`
+	HasVoted        bool                 `json:"has_voted"`
+	ResultsVisible  bool                 `json:"results_visible"`
+	ViewerOptionIDs []uuid.UUID          `json:"viewer_option_ids,omitempty"`
+	Options         []PollOptionResponse `json:"options"`
+}
+
+// PollOptionResponse for a poll option, VotesCount is nil while results are hidden
+type PollOptionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	Text       string    `json:"text"`
+	VotesCount *int      `json:"votes_count,omitempty"`
+}
@@ -8,26 +8,34 @@ import (
 )
 
 type Post struct {
-	ID            uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	UserID        uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
-	Caption       string         `gorm:"type:text" json:"caption"`
-	MediaURL      string         `gorm:"size:255" json:"media_url"`
-	MediaType     string         `gorm:"size:20" json:"media_type"` // image, video, text
-	LikesCount    int            `gorm:"default:0" json:"likes_count"`
-	CommentsCount int            `gorm:"default:0" json:"comments_count"`
-	SharesCount   int            `gorm:"default:0" json:"shares_count"`
-	ViewsCount    int            `gorm:"default:0" json:"views_count"`
-	IsPublic      bool           `gorm:"default:true" json:"is_public"`
-	Location      string         `gorm:"size:100" json:"location,omitempty"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID          uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	Caption         string         `gorm:"type:text" json:"caption"`
+	MediaURL        string         `gorm:"size:255" json:"media_url"`
+	MediaType       string         `gorm:"size:20" json:"media_type"` // image, video, text
+	LikesCount      int            `gorm:"default:0" json:"likes_count"`
+	CommentsCount   int            `gorm:"default:0" json:"comments_count"`
+	SharesCount     int            `gorm:"default:0" json:"shares_count"`
+	ViewsCount      int            `gorm:"default:0" json:"views_count"`
+	IsPublic        bool           `gorm:"default:true" json:"is_public"`
+	Location        string         `gorm:"size:100" json:"location,omitempty"`
+	IsArchived      bool           `gorm:"default:false;index" json:"is_archived"`
+	ArchivedAt      *time.Time     `json:"archived_at,omitempty"`
+	PinPosition     *int           `json:"pin_position,omitempty"`                                    // 1 is the top of the profile grid, nil when not pinned
+	OriginalPostID  *uuid.UUID     `gorm:"type:uuid;index" json:"original_post_id,omitempty"`         // Set for reposts and quote posts
+	ShareType       string         `gorm:"size:20" json:"share_type,omitempty"`                       // repost, quote
+	CommentPolicy   string         `gorm:"size:20;not null;default:'everyone'" json:"comment_policy"` // everyone, followers, off
+	PinnedCommentID *uuid.UUID     `gorm:"type:uuid" json:"pinned_comment_id,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationships
-	User     User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Comments []Comment `gorm:"foreignKey:PostID" json:"comments,omitempty"`
-	Likes    []Like    `gorm:"foreignKey:PostID" json:"likes,omitempty"`
-	Hashtags []Hashtag `gorm:"many2many:post_hashtags;" json:"hashtags,omitempty"`
+	User         User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	OriginalPost *Post     `gorm:"foreignKey:OriginalPostID" json:"original_post,omitempty"`
+	Comments     []Comment `gorm:"foreignKey:PostID" json:"comments,omitempty"`
+	Likes        []Like    `gorm:"foreignKey:PostID" json:"likes,omitempty"`
+	Hashtags     []Hashtag `gorm:"many2many:post_hashtags;" json:"hashtags,omitempty"`
 
 	// Computed fields
 	IsLiked bool `gorm:"-" json:"is_liked,omitempty"`
@@ -47,6 +55,8 @@ type CreatePostRequest struct {
 	MediaType string `json:"media_type" binding:"omitempty,oneof=image video text"`
 	Location  string `json:"location" binding:"omitempty,max=100"`
 	IsPublic  *bool  `json:"is_public"`
+
+	Poll *CreatePollRequest `json:"poll,omitempty"`
 }
 
 // UpdatePostRequest for updating a post
@@ -58,18 +68,72 @@ type UpdatePostRequest struct {
 
 // PostResponse includes user and engagement info
 type PostResponse struct {
-	ID            uuid.UUID    `json:"id"`
-	User          UserResponse `json:"user"`
-	Caption       string       `json:"caption"`
-	MediaURL      string       `json:"media_url"`
-	MediaType     string       `json:"media_type"`
-	LikesCount    int          `json:"likes_count"`
-	CommentsCount int          `json:"comments_count"`
-	SharesCount   int          `json:"shares_count"`
-	ViewsCount    int          `json:"views_count"`
-	Location      string       `json:"location,omitempty"`
-	IsLiked       bool         `json:"is_liked"`
-	IsSaved       bool         `json:"is_saved"`
-	CreatedAt     time.Time    `json:"created_at"`
-	UpdatedAt     time.Time    `json:"updated_at"`
-}
\ No newline at end of file
+	ID              uuid.UUID     `json:"id"`
+	User            UserResponse  `json:"user"`
+	Caption         string        `json:"caption"`
+	MediaURL        string        `json:"media_url"`
+	MediaType       string        `json:"media_type"`
+	LikesCount      int           `json:"likes_count"`
+	CommentsCount   int           `json:"comments_count"`
+	SharesCount     int           `json:"shares_count"`
+	ViewsCount      int           `json:"views_count"`
+	Location        string        `json:"location,omitempty"`
+	IsLiked         bool          `json:"is_liked"` // True for any reaction type, LikesCount counts them all
+	IsSaved         bool          `json:"is_saved"`
+	IsArchived      bool          `json:"is_archived,omitempty"`
+	PinPosition     *int          `json:"pin_position,omitempty"`
+	ShareType       string        `json:"share_type,omitempty"`
+	CommentPolicy   string        `json:"comment_policy"`
+	PinnedCommentID *uuid.UUID    `json:"pinned_comment_id,omitempty"`
+	OriginalPost    *PostResponse `json:"original_post,omitempty"`
+	// OriginalUnavailable is set when the shared post was deleted or the viewer can no longer see it
+	OriginalUnavailable bool              `json:"original_unavailable,omitempty"`
+	Poll                *PollResponse     `json:"poll,omitempty"`
+	ReactionCounts      map[string]int    `json:"reaction_counts,omitempty"`
+	ViewerReaction      string            `json:"viewer_reaction,omitempty"`
+	Mentions            []MentionResponse `json:"mentions,omitempty"`
+	CreatedAt           time.Time         `json:"created_at"`
+	UpdatedAt           time.Time         `json:"updated_at"`
+}
+
+// QuotePostRequest for sharing a post with a new caption
+type QuotePostRequest struct {
+	Caption  string `json:"caption" binding:"required,min=1,max=2200"`
+	Location string `json:"location" binding:"omitempty,max=100"`
+	IsPublic *bool  `json:"is_public"`
+}
+
+// UpdateCommentSettingsRequest for changing who can comment on a post
+type UpdateCommentSettingsRequest struct {
+	CommentPolicy string `json:"comment_policy" binding:"required,oneof=everyone followers off"`
+}
+
+// ReorderPinnedPostsRequest sets the order of pinned posts, top of the grid first
+type ReorderPinnedPostsRequest struct {
+	PostIDs []uuid.UUID `json:"post_ids" binding:"required,max=3,dive,required"`
+}
+
+// ToResponse converts a post to its public representation
+func (p *Post) ToResponse() PostResponse {
+	return PostResponse{
+		ID:              p.ID,
+		User:            p.User.ToResponse(),
+		Caption:         p.Caption,
+		MediaURL:        p.MediaURL,
+		MediaType:       p.MediaType,
+		LikesCount:      p.LikesCount,
+		CommentsCount:   p.CommentsCount,
+		SharesCount:     p.SharesCount,
+		ViewsCount:      p.ViewsCount,
+		Location:        p.Location,
+		IsLiked:         p.IsLiked,
+		IsSaved:         p.IsSaved,
+		IsArchived:      p.IsArchived,
+		PinPosition:     p.PinPosition,
+		ShareType:       p.ShareType,
+		CommentPolicy:   p.CommentPolicy,
+		PinnedCommentID: p.PinnedCommentID,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+	}
+}
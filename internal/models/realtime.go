@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RealtimeEvent is pushed to connected clients over the WebSocket gateway
+type RealtimeEvent struct {
+	Type           string      `json:"type"` // message, message_unsent, read, typing, resync_required, error
+	ConversationID *uuid.UUID  `json:"conversation_id,omitempty"`
+	Data           interface{} `json:"data,omitempty"`
+}
+
+// ClientEvent is sent by clients over the WebSocket gateway: typing with Typing set, or
+// read with an optional MessageID
+type ClientEvent struct {
+	Type           string     `json:"type"`
+	ConversationID uuid.UUID  `json:"conversation_id"`
+	MessageID      *uuid.UUID `json:"message_id,omitempty"`
+	Typing         bool       `json:"typing,omitempty"`
+}
+
+// ReadReceipt reports how far a participant has read
+type ReadReceipt struct {
+	UserID            uuid.UUID  `json:"user_id"`
+	LastReadMessageID *uuid.UUID `json:"last_read_message_id,omitempty"`
+	LastReadAt        *time.Time `json:"last_read_at,omitempty"`
+}
+
+// UnsentMessage reports a message removed for every participant
+type UnsentMessage struct {
+	MessageID uuid.UUID `json:"message_id"`
+}
+
+// TypingIndicator reports a participant starting or stopping to type
+type TypingIndicator struct {
+	UserID uuid.UUID `json:"user_id"`
+	Typing bool      `json:"typing"`
+}
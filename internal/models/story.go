@@ -8,19 +8,20 @@ import (
 )
 
 type Story struct {
-	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	UserID     uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
-	MediaURL   string    `gorm:"not null;size:255" json:"media_url"`
-	MediaType  string    `gorm:"not null;size:20" json:"media_type"` // image, video
-	Caption    string    `gorm:"type:text" json:"caption,omitempty"`
-	ViewsCount int       `gorm:"default:0" json:"views_count"`
-	ExpiresAt  time.Time `gorm:"not null;index" json:"expires_at"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	MediaURL   string         `gorm:"not null;size:255" json:"media_url"`
+	MediaKey   string         `gorm:"size:255" json:"-"`                  // Storage key of the media, cleared once the file is removed
+	MediaType  string         `gorm:"not null;size:20" json:"media_type"` // image, video
+	Caption    string         `gorm:"type:text" json:"caption,omitempty"`
+	ViewsCount int            `gorm:"default:0" json:"views_count"`
+	ExpiresAt  time.Time      `gorm:"not null;index" json:"expires_at"`
+	CreatedAt  time.Time      `json:"created_at"`
 	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationships
-	User  User         `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Views []StoryView  `gorm:"foreignKey:StoryID" json:"views,omitempty"`
+	User  User        `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Views []StoryView `gorm:"foreignKey:StoryID" json:"views,omitempty"`
 
 	// Computed
 	IsViewed bool `gorm:"-" json:"is_viewed,omitempty"`
@@ -37,12 +38,12 @@ func (s *Story) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
-// StoryView tracks who viewed a story
+// StoryView tracks who viewed a story, once per user
 type StoryView struct {
-	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	StoryID   uuid.UUID `gorm:"type:uuid;not null;index" json:"story_id"`
-	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
-	ViewedAt  time.Time `json:"viewed_at"`
+	ID       uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	StoryID  uuid.UUID `gorm:"type:uuid;not null;index" json:"story_id"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	ViewedAt time.Time `json:"viewed_at"`
 
 	// Relationships
 	Story Story `gorm:"foreignKey:StoryID" json:"story,omitempty"`
@@ -59,21 +60,51 @@ func (sv *StoryView) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
-// CreateStoryRequest for creating stories
+// CreateStoryRequest for creating stories, sent as a multipart form along with the media file
 type CreateStoryRequest struct {
-	MediaType string `json:"media_type" binding:"required,oneof=image video"`
-	Caption   string `json:"caption,omitempty" binding:"omitempty,max=500"`
+	MediaType string                `json:"media_type" form:"media_type" binding:"required,oneof=image video"`
+	Caption   string                `json:"caption,omitempty" form:"caption" binding:"omitempty,max=500"`
+	Stickers  []StoryStickerRequest `json:"stickers,omitempty" form:"-" binding:"omitempty,max=10,dive"` // JSON array in the "stickers" form field
 }
 
 // StoryResponse includes user info
 type StoryResponse struct {
-	ID         uuid.UUID    `json:"id"`
-	User       UserResponse `json:"user"`
-	MediaURL   string       `json:"media_url"`
-	MediaType  string       `json:"media_type"`
-	Caption    string       `json:"caption,omitempty"`
-	ViewsCount int          `json:"views_count"`
-	IsViewed   bool         `json:"is_viewed"`
-	ExpiresAt  time.Time    `json:"expires_at"`
-	CreatedAt  time.Time    `json:"created_at"`
-}
\ No newline at end of file
+	ID         uuid.UUID         `json:"id"`
+	User       UserResponse      `json:"user"`
+	MediaURL   string            `json:"media_url"`
+	MediaType  string            `json:"media_type"`
+	Caption    string            `json:"caption,omitempty"`
+	ViewsCount int               `json:"views_count"`
+	IsViewed   bool              `json:"is_viewed"`
+	Mentions   []MentionResponse `json:"mentions,omitempty"`
+	Stickers   []StickerResponse `json:"stickers,omitempty"`
+	ExpiresAt  time.Time         `json:"expires_at"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+func (s *Story) ToResponse() StoryResponse {
+	return StoryResponse{
+		ID:         s.ID,
+		User:       s.User.ToResponse(),
+		MediaURL:   s.MediaURL,
+		MediaType:  s.MediaType,
+		Caption:    s.Caption,
+		ViewsCount: s.ViewsCount,
+		IsViewed:   s.IsViewed,
+		ExpiresAt:  s.ExpiresAt,
+		CreatedAt:  s.CreatedAt,
+	}
+}
+
+// StoryViewerResponse is an entry of a story's viewers list
+type StoryViewerResponse struct {
+	User     UserResponse `json:"user"`
+	ViewedAt time.Time    `json:"viewed_at"`
+}
+
+// StoryTrayItem groups the live stories of one user, oldest first
+type StoryTrayItem struct {
+	User      UserResponse    `json:"user"`
+	Stories   []StoryResponse `json:"stories"`
+	HasUnseen bool            `json:"has_unseen"`
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StorySticker is an interactive element placed on a story. X and Y are the sticker's center
+// relative to the story size, from 0 to 1.
+type StorySticker struct {
+	ID        uuid.UUID   `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	StoryID   uuid.UUID   `gorm:"type:uuid;not null;index" json:"story_id"`
+	Type      string      `gorm:"size:20;not null" json:"type"` // poll, question, slider, mention, link, hashtag
+	X         float64     `gorm:"not null" json:"x"`
+	Y         float64     `gorm:"not null" json:"y"`
+	Rotation  float64     `gorm:"not null;default:0" json:"rotation"` // Degrees
+	Scale     float64     `gorm:"not null;default:1" json:"scale"`
+	Position  int         `gorm:"not null;default:0" json:"position"` // Stacking order, higher is on top
+	Data      StickerData `gorm:"type:jsonb;not null" json:"data"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+func (s *StorySticker) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// StickerData holds the type specific content of a sticker
+type StickerData struct {
+	Question string     `json:"question,omitempty"` // Poll, question and slider prompt
+	Options  []string   `json:"options,omitempty"`  // Poll
+	Emoji    string     `json:"emoji,omitempty"`    // Slider
+	Username string     `json:"username,omitempty"` // Mention
+	UserID   *uuid.UUID `json:"user_id,omitempty"`  // Mention, unset when the user cannot be mentioned
+	URL      string     `json:"url,omitempty"`      // Link
+	Hashtag  string     `json:"hashtag,omitempty"`  // Hashtag, normalized
+}
+
+func (d StickerData) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+func (d *StickerData) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, d)
+	case string:
+		return json.Unmarshal([]byte(v), d)
+	case nil:
+		*d = StickerData{}
+		return nil
+	}
+	return errors.New("unsupported sticker data type")
+}
+
+// StoryStickerAnswer is a user's response to a poll, question or slider sticker
+type StoryStickerAnswer struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	StickerID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_story_sticker_answers_sticker_user" json:"sticker_id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_story_sticker_answers_sticker_user;index" json:"user_id"`
+	OptionIndex *int      `json:"option_index,omitempty"` // Poll
+	Value       *float64  `json:"value,omitempty"`        // Slider, from 0 to 1
+	Text        string    `gorm:"type:text" json:"text,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Relationships
+	Sticker StorySticker `gorm:"foreignKey:StickerID;constraint:OnDelete:CASCADE" json:"sticker,omitempty"`
+	User    User         `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (a *StoryStickerAnswer) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// StoryStickerRequest places a sticker on a story being created. Only the fields of the
+// sticker's type are used.
+type StoryStickerRequest struct {
+	Type     string   `json:"type" binding:"required,oneof=poll question slider mention link hashtag"`
+	X        float64  `json:"x" binding:"min=0,max=1"`
+	Y        float64  `json:"y" binding:"min=0,max=1"`
+	Rotation float64  `json:"rotation" binding:"min=-180,max=180"`
+	Scale    float64  `json:"scale,omitempty" binding:"omitempty,min=0.1,max=5"`
+	Question string   `json:"question,omitempty" binding:"max=100"`
+	Options  []string `json:"options,omitempty" binding:"omitempty,min=2,max=4,dive,required,max=50"`
+	Emoji    string   `json:"emoji,omitempty" binding:"max=16"`
+	Username string   `json:"username,omitempty" binding:"max=50"`
+	URL      string   `json:"url,omitempty" binding:"omitempty,url,max=255"`
+	Hashtag  string   `json:"hashtag,omitempty" binding:"max=100"`
+}
+
+// StickerAnswerRequest answers a sticker: option_index for polls, value for sliders and
+// text for questions
+type StickerAnswerRequest struct {
+	OptionIndex *int     `json:"option_index,omitempty" binding:"omitempty,min=0"`
+	Value       *float64 `json:"value,omitempty" binding:"omitempty,min=0,max=1"`
+	Text        string   `json:"text,omitempty" binding:"max=300"`
+}
+
+// StickerResponse for sticker data. Results are shown to the author, and to viewers of poll
+// and slider stickers once they answered.
+type StickerResponse struct {
+	ID       uuid.UUID       `json:"id"`
+	Type     string          `json:"type"`
+	X        float64         `json:"x"`
+	Y        float64         `json:"y"`
+	Rotation float64         `json:"rotation"`
+	Scale    float64         `json:"scale"`
+	Data     StickerData     `json:"data"`
+	Answered bool            `json:"answered"`
+	Results  *StickerResults `json:"results,omitempty"`
+}
+
+// StickerResults aggregates the answers to a sticker
+type StickerResults struct {
+	Total        int      `json:"total"`
+	OptionCounts []int    `json:"option_counts,omitempty"` // Poll, in option order
+	Average      *float64 `json:"average,omitempty"`       // Slider
+}
+
+// StickerAnswerResponse is an answer as listed to the story author
+type StickerAnswerResponse struct {
+	User        UserResponse `json:"user"`
+	OptionIndex *int         `json:"option_index,omitempty"`
+	Value       *float64     `json:"value,omitempty"`
+	Text        string       `json:"text,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
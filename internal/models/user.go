@@ -8,27 +8,31 @@ import (
 )
 
 type User struct {
-	ID              uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	Username			string     `gorm:"uniqueIndex;not null;size:50" json:"username"`
-	Email					 string     `gorm:"uniqueIndex;not null;size:100" json:"email"`
-	Password        string     `gorm:"not null" json:"-"`
-	FullName        string     `gorm:"size:100" json:"full_name"`
-	Bio             string     `gorm:"type:text" json:"bio"`
-	ProfileImageURL string     `gorm:"size:255" json:"profile_image_url"`
-	CoverImageURL   string     `gorm:"size:255" json:"cover_image_url"`
-	Website         string     `gorm:"size:100" json:"website"`
-	Location        string     `gorm:"size:100" json:"location"`
-	DateOfBirth     *time.Time `json:"date_of_birth"`
-	Role            string     `gorm:"default:'user';size:20" json:"role"`
-	IsVerified      bool       `gorm:"default:false" json:"is_verified"`
-	IsPrivate       bool       `gorm:"default:false" json:"is_private"`
-	IsActive        bool       `gorm:"default:true" json:"is_active"`
-	LastLoginAt     *time.Time `json:"last_login_at"`
-	CreatedAt       time.Time  `json:"created_at"`
-	UpdatedAt       time.Time  `json:"updated_at"`
-	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+	ID               uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Username         string         `gorm:"uniqueIndex;not null;size:50" json:"username"`
+	Email            string         `gorm:"uniqueIndex;not null;size:100" json:"email"`
+	Password         string         `gorm:"not null" json:"-"`
+	FullName         string         `gorm:"size:100" json:"full_name"`
+	Bio              string         `gorm:"type:text" json:"bio"`
+	ProfileImageURL  string         `gorm:"size:255" json:"profile_image_url"`
+	CoverImageURL    string         `gorm:"size:255" json:"cover_image_url"`
+	Website          string         `gorm:"size:100" json:"website"`
+	Location         string         `gorm:"size:100" json:"location"`
+	DateOfBirth      *time.Time     `json:"date_of_birth"`
+	Role             string         `gorm:"default:'user';size:20" json:"role"`
+	IsVerified       bool           `gorm:"default:false" json:"is_verified"`
+	IsPrivate        bool           `gorm:"default:false" json:"is_private"`
+	IsActive         bool           `gorm:"default:true" json:"is_active"`
+	MentionPolicy    string         `gorm:"size:20;not null;default:'everyone'" json:"mention_policy"`     // everyone, following, nobody
+	Region           string         `gorm:"size:2;index" json:"region,omitempty"`                          // ISO 3166-1 alpha-2 country code
+	StoryReplyPolicy string         `gorm:"size:20;not null;default:'everyone'" json:"story_reply_policy"` // everyone, followers, off
+	MessagePolicy    string         `gorm:"size:20;not null;default:'everyone'" json:"message_policy"`     // everyone, followers, nobody
+	LastLoginAt      *time.Time     `json:"last_login_at"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
 
-		// Relationships
+	// Relationships
 	Posts         []Post         `gorm:"foreignKey:UserID" json:"posts,omitempty"`
 	Comments      []Comment      `gorm:"foreignKey:UserID" json:"comments,omitempty"`
 	Likes         []Like         `gorm:"foreignKey:UserID" json:"likes,omitempty"`
@@ -36,7 +40,6 @@ type User struct {
 	Followers     []Follow       `gorm:"foreignKey:FollowingID" json:"followers,omitempty"`
 	Following     []Follow       `gorm:"foreignKey:FollowerID" json:"following,omitempty"`
 	SentMessages  []Message      `gorm:"foreignKey:SenderID" json:"sent_messages,omitempty"`
-	ReceivedMessages []Message   `gorm:"foreignKey:ReceiverID" json:"received_messages,omitempty"`
 	Notifications []Notification `gorm:"foreignKey:UserID" json:"notifications,omitempty"`
 
 	// Counts (not stored in DB, computed)
@@ -54,23 +57,43 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 
 // UserResponse is used for public user data
 type UserResponse struct {
-	ID              uuid.UUID  `json:"id"`
-	Username        string     `json:"username"`
-	Email           string     `json:"email,omitempty"` // Only shown to self
-	FullName        string     `json:"full_name"`
-	Bio             string     `json:"bio"`
-	ProfileImageURL string     `json:"profile_image_url"`
-	CoverImageURL   string     `json:"cover_image_url"`
-	Website         string     `json:"website"`
-	Location        string     `json:"location"`
-	IsVerified      bool       `json:"is_verified"`
-	IsPrivate       bool       `json:"is_private"`
-	FollowersCount  int        `json:"followers_count"`
-	FollowingCount  int        `json:"following_count"`
-	PostsCount      int        `json:"posts_count"`
-	IsFollowing     bool       `json:"is_following,omitempty"`
-	IsFollowedBy    bool       `json:"is_followed_by,omitempty"`
-	CreatedAt       time.Time  `json:"created_at"`
+	ID              uuid.UUID `json:"id"`
+	Username        string    `json:"username"`
+	Email           string    `json:"email,omitempty"` // Only shown to self
+	FullName        string    `json:"full_name"`
+	Bio             string    `json:"bio"`
+	ProfileImageURL string    `json:"profile_image_url"`
+	CoverImageURL   string    `json:"cover_image_url"`
+	Website         string    `json:"website"`
+	Location        string    `json:"location"`
+	IsVerified      bool      `json:"is_verified"`
+	IsPrivate       bool      `json:"is_private"`
+	FollowersCount  int       `json:"followers_count"`
+	FollowingCount  int       `json:"following_count"`
+	PostsCount      int       `json:"posts_count"`
+	IsFollowing     bool      `json:"is_following,omitempty"`
+	IsFollowedBy    bool      `json:"is_followed_by,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// ToResponse converts a user to its public representation
+func (u *User) ToResponse() UserResponse {
+	return UserResponse{
+		ID:              u.ID,
+		Username:        u.Username,
+		FullName:        u.FullName,
+		Bio:             u.Bio,
+		ProfileImageURL: u.ProfileImageURL,
+		CoverImageURL:   u.CoverImageURL,
+		Website:         u.Website,
+		Location:        u.Location,
+		IsVerified:      u.IsVerified,
+		IsPrivate:       u.IsPrivate,
+		FollowersCount:  u.FollowersCount,
+		FollowingCount:  u.FollowingCount,
+		PostsCount:      u.PostsCount,
+		CreatedAt:       u.CreatedAt,
+	}
 }
 
 // LoginRequest for user login
@@ -94,4 +117,20 @@ type UpdateProfileRequest struct {
 	Website   string `json:"website,omitempty" binding:"omitempty,url,max=100"`
 	Location  string `json:"location,omitempty" binding:"omitempty,max=100"`
 	IsPrivate *bool  `json:"is_private,omitempty"`
-}
\ No newline at end of file
+}
+
+// UpdateSettingsRequest for updating privacy settings, only the given fields change
+type UpdateSettingsRequest struct {
+	MentionPolicy    *string `json:"mention_policy,omitempty" binding:"omitempty,oneof=everyone following nobody"`
+	Region           *string `json:"region,omitempty" binding:"omitempty,iso3166_1_alpha2"`
+	StoryReplyPolicy *string `json:"story_reply_policy,omitempty" binding:"omitempty,oneof=everyone followers off"`
+	MessagePolicy    *string `json:"message_policy,omitempty" binding:"omitempty,oneof=everyone followers nobody"`
+}
+
+// SettingsResponse for the user's privacy settings
+type SettingsResponse struct {
+	MentionPolicy    string `json:"mention_policy"`
+	Region           string `json:"region,omitempty"`
+	StoryReplyPolicy string `json:"story_reply_policy"`
+	MessagePolicy    string `json:"message_policy"`
+}
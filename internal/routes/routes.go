@@ -0,0 +1,223 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"social-media-backend/internal/config"
+	"social-media-backend/internal/handlers"
+	"social-media-backend/internal/middleware"
+	"social-media-backend/internal/services"
+	"social-media-backend/internal/storage"
+	"social-media-backend/pkg/constants"
+)
+
+func SetupRoutes(router *gin.Engine, db *gorm.DB, rdb *redis.Client, cfg *config.Config, hub *services.RealtimeHub, feedFanout *services.FeedFanout) {
+	mediaStorage := storage.NewLocalStorage(cfg.Upload.Path, constants.UploadURLPrefix)
+	router.Static(constants.UploadURLPrefix, cfg.Upload.Path)
+
+	// Services
+	notificationService := services.NewNotificationService(db)
+	postService := services.NewPostService(db, notificationService, feedFanout)
+	collectionService := services.NewCollectionService(db, postService)
+	shareService := services.NewShareService(db, postService, notificationService)
+	pollService := services.NewPollService(db, postService)
+	reactionService := services.NewReactionService(db, postService, notificationService)
+	commentService := services.NewCommentService(db, postService, notificationService)
+	moderationService := services.NewCommentModerationService(db, postService, commentService)
+	hashtagService := services.NewHashtagService(db, postService)
+	trendingService := services.NewTrendingService(db, rdb)
+	feedService := services.NewFeedService(db, rdb, postService, feedFanout, services.NewWeightedScorer())
+	userService := services.NewUserService(db)
+	blockService := services.NewBlockService(db, feedFanout)
+	muteService := services.NewMuteService(db)
+	mentionService := services.NewMentionService(db, postService)
+	exploreService := services.NewExploreService(db, rdb, postService)
+	followService := services.NewFollowService(db)
+	messageService := services.NewMessageService(db, mediaStorage, hub)
+	storyService := services.NewStoryService(db, mediaStorage, notificationService, messageService)
+	highlightService := services.NewHighlightService(db, mediaStorage, storyService)
+	conversationService := services.NewConversationService(db, mediaStorage, hub, blockService)
+
+	// Handlers
+	postHandler := handlers.NewPostHandler(postService)
+	collectionHandler := handlers.NewCollectionHandler(collectionService)
+	shareHandler := handlers.NewShareHandler(shareService)
+	pollHandler := handlers.NewPollHandler(pollService)
+	reactionHandler := handlers.NewReactionHandler(reactionService)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	moderationHandler := handlers.NewCommentModerationHandler(moderationService)
+	hashtagHandler := handlers.NewHashtagHandler(hashtagService, trendingService)
+	feedHandler := handlers.NewFeedHandler(feedService)
+	exploreHandler := handlers.NewExploreHandler(exploreService)
+	userHandler := handlers.NewUserHandler(userService, blockService, muteService, mentionService, followService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	storyHandler := handlers.NewStoryHandler(storyService)
+	highlightHandler := handlers.NewHighlightHandler(highlightService)
+	conversationHandler := handlers.NewConversationHandler(conversationService)
+	messageHandler := handlers.NewMessageHandler(messageService)
+	realtimeHandler := handlers.NewRealtimeHandler(hub, messageService, conversationService, cfg.Server.AllowedOrigins)
+
+	auth := middleware.AuthMiddleware(cfg.JWT.Secret)
+	optionalAuth := middleware.OptionalAuthMiddleware(cfg.JWT.Secret)
+	wsAuth := middleware.WebSocketAuthMiddleware(cfg.JWT.Secret)
+
+	api := router.Group("/api/v1")
+
+	posts := api.Group("/posts")
+	{
+		posts.POST("", auth, postHandler.CreatePost)
+		posts.GET("/archived", auth, postHandler.GetArchivedPosts)
+		posts.PUT("/pinned", auth, postHandler.ReorderPinnedPosts)
+		posts.GET("/:id", optionalAuth, postHandler.GetPost)
+		posts.PUT("/:id", auth, postHandler.UpdatePost)
+		posts.DELETE("/:id", auth, postHandler.DeletePost)
+		posts.POST("/:id/archive", auth, postHandler.ArchivePost)
+		posts.DELETE("/:id/archive", auth, postHandler.UnarchivePost)
+		posts.POST("/:id/pin", auth, postHandler.PinPost)
+		posts.DELETE("/:id/pin", auth, postHandler.UnpinPost)
+		posts.POST("/:id/save", auth, collectionHandler.SavePost)
+		posts.DELETE("/:id/save", auth, collectionHandler.UnsavePost)
+		posts.POST("/:id/repost", auth, shareHandler.Repost)
+		posts.DELETE("/:id/repost", auth, shareHandler.UndoRepost)
+		posts.POST("/:id/quote", auth, shareHandler.QuotePost)
+		posts.POST("/:id/poll/votes", auth, pollHandler.Vote)
+		posts.GET("/:id/comments", optionalAuth, commentHandler.ListComments)
+		posts.GET("/:id/comments/held", auth, moderationHandler.ListHeldComments)
+		posts.PATCH("/:id/comment-settings", auth, moderationHandler.UpdateCommentSettings)
+		posts.GET("/:id/reactions", optionalAuth, reactionHandler.ListReactions(services.ReactionTargetPost))
+		posts.PUT("/:id/reactions", auth, reactionHandler.React(services.ReactionTargetPost))
+		posts.DELETE("/:id/reactions", auth, reactionHandler.RemoveReaction(services.ReactionTargetPost))
+	}
+
+	hashtags := api.Group("/hashtags")
+	{
+		hashtags.GET("/trending", hashtagHandler.GetTrending)
+		hashtags.GET("/:name", optionalAuth, hashtagHandler.GetHashtag)
+		hashtags.GET("/:name/usage", hashtagHandler.GetHashtagUsage)
+		hashtags.GET("/:name/posts", optionalAuth, hashtagHandler.GetHashtagPosts)
+		hashtags.POST("/:name/follow", auth, hashtagHandler.FollowHashtag)
+		hashtags.DELETE("/:name/follow", auth, hashtagHandler.UnfollowHashtag)
+	}
+
+	api.GET("/feed", auth, feedHandler.GetHomeFeed)
+	api.GET("/explore", optionalAuth, exploreHandler.GetExplore)
+
+	likes := api.Group("/likes", auth)
+	{
+		likes.POST("", reactionHandler.Like)
+		likes.DELETE("", reactionHandler.Unlike)
+	}
+
+	comments := api.Group("/comments")
+	{
+		comments.POST("", auth, commentHandler.CreateComment)
+		comments.GET("/:id", optionalAuth, commentHandler.GetComment)
+		comments.PUT("/:id", auth, commentHandler.UpdateComment)
+		comments.DELETE("/:id", auth, commentHandler.DeleteComment)
+		comments.GET("/:id/replies", optionalAuth, commentHandler.ListReplies)
+		comments.POST("/:id/hide", auth, moderationHandler.HideComment)
+		comments.POST("/:id/approve", auth, moderationHandler.ApproveComment)
+		comments.POST("/:id/pin", auth, moderationHandler.PinComment)
+		comments.DELETE("/:id/pin", auth, moderationHandler.UnpinComment)
+		comments.GET("/:id/reactions", optionalAuth, reactionHandler.ListReactions(services.ReactionTargetComment))
+		comments.PUT("/:id/reactions", auth, reactionHandler.React(services.ReactionTargetComment))
+		comments.DELETE("/:id/reactions", auth, reactionHandler.RemoveReaction(services.ReactionTargetComment))
+	}
+
+	api.GET("/ws", wsAuth, realtimeHandler.Connect)
+
+	conversations := api.Group("/conversations", auth)
+	{
+		conversations.POST("", conversationHandler.CreateConversation)
+		conversations.GET("", conversationHandler.GetConversations)
+		conversations.GET("/requests", conversationHandler.GetMessageRequests)
+		conversations.GET("/:id", conversationHandler.GetConversation)
+		conversations.PUT("/:id", conversationHandler.UpdateConversation)
+		conversations.PUT("/:id/avatar", conversationHandler.SetAvatar)
+		conversations.POST("/:id/participants", conversationHandler.AddParticipants)
+		conversations.DELETE("/:id/participants/:user_id", conversationHandler.RemoveParticipant)
+		conversations.PUT("/:id/participants/:user_id/role", conversationHandler.UpdateParticipantRole)
+		conversations.POST("/:id/leave", conversationHandler.LeaveConversation)
+		conversations.POST("/:id/read", conversationHandler.MarkRead)
+		conversations.POST("/:id/accept", conversationHandler.AcceptRequest)
+		conversations.POST("/:id/decline", conversationHandler.DeclineRequest)
+		conversations.POST("/:id/block", conversationHandler.BlockRequest)
+		conversations.GET("/:id/messages", messageHandler.GetMessages)
+		conversations.POST("/:id/messages", messageHandler.SendMessage)
+	}
+
+	messages := api.Group("/messages", auth)
+	{
+		messages.POST("", messageHandler.SendDirectMessage)
+		messages.DELETE("/:id", messageHandler.DeleteMessage)
+		messages.POST("/:id/unsend", messageHandler.UnsendMessage)
+		messages.GET("/:id/reactions", reactionHandler.ListReactions(services.ReactionTargetMessage))
+		messages.PUT("/:id/reactions", reactionHandler.React(services.ReactionTargetMessage))
+		messages.DELETE("/:id/reactions", reactionHandler.RemoveReaction(services.ReactionTargetMessage))
+	}
+
+	stories := api.Group("/stories", auth)
+	{
+		stories.POST("", storyHandler.CreateStory)
+		stories.GET("/tray", storyHandler.GetStoryTray)
+		stories.GET("/:id", storyHandler.GetStory)
+		stories.DELETE("/:id", storyHandler.DeleteStory)
+		stories.POST("/:id/views", storyHandler.RecordView)
+		stories.GET("/:id/views", storyHandler.GetStoryViewers)
+		stories.POST("/:id/replies", storyHandler.ReplyToStory)
+		stories.POST("/:id/reactions", storyHandler.ReactToStory)
+		stories.POST("/:id/stickers/:sticker_id/answers", storyHandler.AnswerSticker)
+		stories.GET("/:id/stickers/:sticker_id/answers", storyHandler.GetStickerAnswers)
+	}
+
+	highlights := api.Group("/highlights")
+	{
+		highlights.POST("", auth, highlightHandler.CreateHighlight)
+		highlights.GET("/:id", optionalAuth, highlightHandler.GetHighlight)
+		highlights.PATCH("/:id", auth, highlightHandler.UpdateHighlight)
+		highlights.DELETE("/:id", auth, highlightHandler.DeleteHighlight)
+		highlights.PUT("/:id/cover", auth, highlightHandler.SetCover)
+		highlights.POST("/:id/stories", auth, highlightHandler.AddStories)
+		highlights.PUT("/:id/stories/order", auth, highlightHandler.ReorderStories)
+		highlights.DELETE("/:id/stories/:story_id", auth, highlightHandler.RemoveStory)
+	}
+
+	api.GET("/notifications", auth, notificationHandler.GetNotifications)
+
+	api.GET("/saved", auth, collectionHandler.GetSavedPosts)
+
+	collections := api.Group("/collections", auth)
+	{
+		collections.GET("", collectionHandler.GetCollections)
+		collections.POST("", collectionHandler.CreateCollection)
+		collections.PUT("/order", collectionHandler.ReorderCollections)
+		collections.PATCH("/:id", collectionHandler.RenameCollection)
+		collections.DELETE("/:id", collectionHandler.DeleteCollection)
+		collections.GET("/:id/posts", collectionHandler.GetCollectionPosts)
+		collections.POST("/:id/posts/:post_id", collectionHandler.AddToCollection)
+		collections.DELETE("/:id/posts/:post_id", collectionHandler.RemoveFromCollection)
+	}
+
+	users := api.Group("/users")
+	{
+		users.GET("/me/settings", auth, userHandler.GetSettings)
+		users.PATCH("/me/settings", auth, userHandler.UpdateSettings)
+		users.GET("/me/blocks", auth, userHandler.GetBlockedUsers)
+		users.GET("/me/mutes", auth, userHandler.GetMutedUsers)
+		users.GET("/me/comment-filters", auth, moderationHandler.GetFilterKeywords)
+		users.POST("/me/comment-filters", auth, moderationHandler.AddFilterKeyword)
+		users.DELETE("/me/comment-filters/:id", auth, moderationHandler.DeleteFilterKeyword)
+		users.GET("/me/hashtags", auth, hashtagHandler.GetFollowedHashtags)
+		users.GET("/:id/posts", optionalAuth, postHandler.GetUserPosts)
+		users.GET("/:id/mentions", optionalAuth, userHandler.GetMentionedPosts)
+		users.GET("/:id/followers", optionalAuth, userHandler.GetFollowers)
+		users.GET("/:id/following", optionalAuth, userHandler.GetFollowing)
+		users.GET("/:id/highlights", optionalAuth, highlightHandler.GetUserHighlights)
+		users.POST("/:id/block", auth, userHandler.BlockUser)
+		users.DELETE("/:id/block", auth, userHandler.UnblockUser)
+		users.POST("/:id/mute", auth, userHandler.MuteUser)
+		users.DELETE("/:id/mute", auth, userHandler.UnmuteUser)
+	}
+}
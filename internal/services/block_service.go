@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"social-media-backend/internal/models"
+	apperrors "social-media-backend/pkg/errors"
+	"social-media-backend/pkg/pagination"
+)
+
+type BlockService struct {
+	db     *gorm.DB
+	fanout *FeedFanout
+}
+
+func NewBlockService(db *gorm.DB, fanout *FeedFanout) *BlockService {
+	return &BlockService{db: db, fanout: fanout}
+}
+
+// BlockUser blocks another user and removes the follows between them in both directions,
+// dropping both cached home feeds. Blocking twice is a no-op.
+func (s *BlockService) BlockUser(blockerID, blockedID uuid.UUID) error {
+	if blockerID == blockedID {
+		return apperrors.ErrCannotBlockSelf
+	}
+	if err := s.db.Select("id").First(&models.User{}, "id = ?", blockedID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrUserNotFound
+		}
+		return err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.Block{BlockerID: blockerID, BlockedID: blockedID}).Error; err != nil {
+			return err
+		}
+		return tx.Where("(follower_id = ? AND following_id = ?) OR (follower_id = ? AND following_id = ?)",
+			blockerID, blockedID, blockedID, blockerID).
+			Delete(&models.Follow{}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	s.fanout.Invalidate(context.Background(), blockerID, blockedID)
+	return nil
+}
+
+// UnblockUser lifts a block. Unblocking a user who is not blocked is a no-op.
+func (s *BlockService) UnblockUser(blockerID, blockedID uuid.UUID) error {
+	return s.db.Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Delete(&models.Block{}).Error
+}
+
+// GetBlockedUsers lists the users the user has blocked, most recent first
+func (s *BlockService) GetBlockedUsers(userID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	query := s.db.Preload("Blocked").Where("blocker_id = ?", userID)
+	if cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	var blocks []models.Block
+	if err := query.Order("created_at DESC, id DESC").
+		Limit(limit + 1).
+		Find(&blocks).Error; err != nil {
+		return nil, err
+	}
+
+	hasMore := len(blocks) > limit
+	if hasMore {
+		blocks = blocks[:limit]
+	}
+
+	users := make([]models.UserResponse, len(blocks))
+	for i, block := range blocks {
+		users[i] = block.Blocked.ToResponse()
+	}
+
+	page := &pagination.Page{Items: users, HasMore: hasMore}
+	if hasMore {
+		last := blocks[len(blocks)-1]
+		page.NextCursor = pagination.NewCursor(last.CreatedAt, last.ID).Encode()
+	}
+	return page, nil
+}
+
+// blockedAmong reports which of userIDs have a block with userID in either direction
+func blockedAmong(db *gorm.DB, userID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	blocked := map[uuid.UUID]bool{}
+	if len(userIDs) == 0 {
+		return blocked, nil
+	}
+
+	var blocks []models.Block
+	if err := db.Where("(blocker_id = ? AND blocked_id IN ?) OR (blocked_id = ? AND blocker_id IN ?)",
+		userID, userIDs, userID, userIDs).
+		Find(&blocks).Error; err != nil {
+		return nil, err
+	}
+	for _, block := range blocks {
+		if block.BlockerID == userID {
+			blocked[block.BlockedID] = true
+		} else {
+			blocked[block.BlockerID] = true
+		}
+	}
+	return blocked, nil
+}
+
+// notBlockedScope hides posts of users who blocked or were blocked by the viewer
+func notBlockedScope(viewerID uuid.UUID) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if viewerID == uuid.Nil {
+			return db
+		}
+		return db.Where(
+			`posts.user_id NOT IN (SELECT blocked_id FROM blocks WHERE blocker_id = ?)
+			AND posts.user_id NOT IN (SELECT blocker_id FROM blocks WHERE blocked_id = ?)`,
+			viewerID, viewerID,
+		)
+	}
+}
@@ -0,0 +1,362 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"social-media-backend/internal/models"
+	apperrors "social-media-backend/pkg/errors"
+	"social-media-backend/pkg/pagination"
+)
+
+type CollectionService struct {
+	db          *gorm.DB
+	postService *PostService
+}
+
+func NewCollectionService(db *gorm.DB, postService *PostService) *CollectionService {
+	return &CollectionService{db: db, postService: postService}
+}
+
+// savedRow is one entry of a keyset paginated list of saved posts
+type savedRow struct {
+	ID        uuid.UUID
+	PostID    uuid.UUID
+	CreatedAt time.Time
+}
+
+// SavePost bookmarks a post and adds it to the given collections. Saving twice is a no-op.
+func (s *CollectionService) SavePost(userID, postID uuid.UUID, collectionIDs []uuid.UUID) (*models.PostResponse, error) {
+	if _, err := s.postService.GetPost(userID, postID); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.SavedPost{UserID: userID, PostID: postID}).Error; err != nil {
+			return err
+		}
+
+		for _, collectionID := range collectionIDs {
+			if _, err := findOwnedCollection(tx, userID, collectionID); err != nil {
+				return err
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&models.CollectionItem{CollectionID: collectionID, PostID: postID}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.postService.GetPost(userID, postID)
+}
+
+// UnsavePost removes a bookmark and takes the post out of every collection
+func (s *CollectionService) UnsavePost(userID, postID uuid.UUID) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND post_id = ?", userID, postID).Delete(&models.SavedPost{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperrors.ErrPostNotSaved
+		}
+
+		return tx.Where("post_id = ? AND collection_id IN (?)", postID,
+			tx.Model(&models.Collection{}).Select("id").Where("user_id = ?", userID),
+		).Delete(&models.CollectionItem{}).Error
+	})
+}
+
+// GetSavedPosts lists all saved posts, most recently saved first
+func (s *CollectionService) GetSavedPosts(userID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	query := s.db.Table("saved_posts").
+		Select("saved_posts.id, saved_posts.post_id, saved_posts.created_at").
+		Joins("JOIN posts ON posts.id = saved_posts.post_id AND posts.deleted_at IS NULL").
+		Where("saved_posts.user_id = ?", userID).
+		Scopes(visiblePostsScope(userID))
+	if cursor != nil {
+		query = query.Where("(saved_posts.created_at, saved_posts.id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	var rows []savedRow
+	if err := query.Order("saved_posts.created_at DESC, saved_posts.id DESC").
+		Limit(limit + 1).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return s.buildSavedPage(userID, rows, limit)
+}
+
+// GetCollections lists the user's collections in their display order
+func (s *CollectionService) GetCollections(userID uuid.UUID) ([]models.CollectionResponse, error) {
+	var collections []models.Collection
+	if err := s.db.Where("user_id = ?", userID).
+		Order("position ASC, created_at ASC").
+		Find(&collections).Error; err != nil {
+		return nil, err
+	}
+
+	return s.buildCollectionResponses(collections)
+}
+
+// CreateCollection adds a collection after the existing ones
+func (s *CollectionService) CreateCollection(userID uuid.UUID, name string) (*models.CollectionResponse, error) {
+	collection := models.Collection{UserID: userID, Name: name}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, userID); err != nil {
+			return err
+		}
+
+		var maxPosition int
+		if err := tx.Model(&models.Collection{}).
+			Where("user_id = ?", userID).
+			Select("COALESCE(MAX(position), 0)").
+			Scan(&maxPosition).Error; err != nil {
+			return err
+		}
+		collection.Position = maxPosition + 1
+
+		if err := tx.Create(&collection).Error; err != nil {
+			if isUniqueViolation(err) {
+				return apperrors.ErrCollectionNameTaken
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := s.buildCollectionResponses([]models.Collection{collection})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// RenameCollection changes the name of a collection
+func (s *CollectionService) RenameCollection(userID, collectionID uuid.UUID, name string) (*models.CollectionResponse, error) {
+	collection, err := findOwnedCollection(s.db, userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(collection).Update("name", name).Error; err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperrors.ErrCollectionNameTaken
+		}
+		return nil, err
+	}
+
+	responses, err := s.buildCollectionResponses([]models.Collection{*collection})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// ReorderCollections sets the display order. The request must list every collection of the user.
+func (s *CollectionService) ReorderCollections(userID uuid.UUID, collectionIDs []uuid.UUID) ([]models.CollectionResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, userID); err != nil {
+			return err
+		}
+
+		var existing []uuid.UUID
+		if err := tx.Model(&models.Collection{}).Where("user_id = ?", userID).Pluck("id", &existing).Error; err != nil {
+			return err
+		}
+		if len(existing) != len(collectionIDs) {
+			return apperrors.ErrInvalidInput
+		}
+
+		remaining := make(map[uuid.UUID]bool, len(existing))
+		for _, id := range existing {
+			remaining[id] = true
+		}
+		for _, id := range collectionIDs {
+			if !remaining[id] {
+				return apperrors.ErrCollectionNotFound
+			}
+			delete(remaining, id)
+		}
+
+		for i, id := range collectionIDs {
+			if err := tx.Model(&models.Collection{}).Where("id = ?", id).Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetCollections(userID)
+}
+
+// DeleteCollection removes a collection. The posts in it stay saved.
+func (s *CollectionService) DeleteCollection(userID, collectionID uuid.UUID) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		collection, err := findOwnedCollection(tx, userID, collectionID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("collection_id = ?", collection.ID).Delete(&models.CollectionItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(collection).Error
+	})
+}
+
+// AddToCollection saves the post if needed and adds it to the collection
+func (s *CollectionService) AddToCollection(userID, collectionID, postID uuid.UUID) (*models.PostResponse, error) {
+	return s.SavePost(userID, postID, []uuid.UUID{collectionID})
+}
+
+// RemoveFromCollection takes a post out of a collection without unsaving it
+func (s *CollectionService) RemoveFromCollection(userID, collectionID, postID uuid.UUID) error {
+	collection, err := findOwnedCollection(s.db, userID, collectionID)
+	if err != nil {
+		return err
+	}
+
+	result := s.db.Where("collection_id = ? AND post_id = ?", collection.ID, postID).Delete(&models.CollectionItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrPostNotSaved
+	}
+	return nil
+}
+
+// GetCollectionPosts lists the posts of a collection, most recently added first
+func (s *CollectionService) GetCollectionPosts(userID, collectionID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	if _, err := findOwnedCollection(s.db, userID, collectionID); err != nil {
+		return nil, err
+	}
+
+	query := s.db.Table("collection_items").
+		Select("collection_items.id, collection_items.post_id, collection_items.created_at").
+		Joins("JOIN posts ON posts.id = collection_items.post_id AND posts.deleted_at IS NULL").
+		Where("collection_items.collection_id = ?", collectionID).
+		Scopes(visiblePostsScope(userID))
+	if cursor != nil {
+		query = query.Where("(collection_items.created_at, collection_items.id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	var rows []savedRow
+	if err := query.Order("collection_items.created_at DESC, collection_items.id DESC").
+		Limit(limit + 1).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return s.buildSavedPage(userID, rows, limit)
+}
+
+func (s *CollectionService) buildSavedPage(userID uuid.UUID, rows []savedRow, limit int) (*pagination.Page, error) {
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.PostID
+	}
+
+	posts, err := s.postService.loadPostsInOrder(ids)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := s.postService.buildPostResponses(userID, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &pagination.Page{Items: responses, HasMore: hasMore}
+	if hasMore {
+		last := rows[len(rows)-1]
+		page.NextCursor = pagination.NewCursor(last.CreatedAt, last.ID).Encode()
+	}
+	return page, nil
+}
+
+// buildCollectionResponses fills post counts and cover images with one query
+func (s *CollectionService) buildCollectionResponses(collections []models.Collection) ([]models.CollectionResponse, error) {
+	responses := make([]models.CollectionResponse, 0, len(collections))
+	if len(collections) == 0 {
+		return responses, nil
+	}
+
+	ids := make([]uuid.UUID, len(collections))
+	for i, collection := range collections {
+		ids[i] = collection.ID
+	}
+
+	type collectionStats struct {
+		CollectionID uuid.UUID
+		PostsCount   int
+		CoverURL     string
+	}
+	var stats []collectionStats
+	if err := s.db.Raw(`
+		SELECT ci.collection_id,
+			COUNT(*) AS posts_count,
+			COALESCE((ARRAY_AGG(p.media_url ORDER BY ci.created_at DESC) FILTER (WHERE p.media_url <> ''))[1], '') AS cover_url
+		FROM collection_items ci
+		JOIN posts p ON p.id = ci.post_id AND p.deleted_at IS NULL
+		WHERE ci.collection_id IN ?
+		GROUP BY ci.collection_id`, ids).Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]collectionStats, len(stats))
+	for _, stat := range stats {
+		byID[stat.CollectionID] = stat
+	}
+
+	for _, collection := range collections {
+		stat := byID[collection.ID]
+		responses = append(responses, models.CollectionResponse{
+			ID:         collection.ID,
+			Name:       collection.Name,
+			Position:   collection.Position,
+			PostsCount: stat.PostsCount,
+			CoverURL:   stat.CoverURL,
+			CreatedAt:  collection.CreatedAt,
+			UpdatedAt:  collection.UpdatedAt,
+		})
+	}
+	return responses, nil
+}
+
+func findOwnedCollection(tx *gorm.DB, userID, collectionID uuid.UUID) (*models.Collection, error) {
+	var collection models.Collection
+	if err := tx.First(&collection, "id = ?", collectionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrCollectionNotFound
+		}
+		return nil, err
+	}
+	if collection.UserID != userID {
+		// Other users' collections are private
+		return nil, apperrors.ErrCollectionNotFound
+	}
+	return &collection, nil
+}
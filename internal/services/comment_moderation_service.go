@@ -0,0 +1,213 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/pkg/constants"
+	apperrors "social-media-backend/pkg/errors"
+	"social-media-backend/pkg/pagination"
+)
+
+// CommentModerationService gives post authors control over their comment sections
+type CommentModerationService struct {
+	db             *gorm.DB
+	postService    *PostService
+	commentService *CommentService
+}
+
+func NewCommentModerationService(db *gorm.DB, postService *PostService, commentService *CommentService) *CommentModerationService {
+	return &CommentModerationService{db: db, postService: postService, commentService: commentService}
+}
+
+// UpdateCommentSettings changes who can comment on the user's post
+func (s *CommentModerationService) UpdateCommentSettings(userID, postID uuid.UUID, policy string) (*models.PostResponse, error) {
+	post, err := s.postService.findOwnedPost(userID, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(post).Update("comment_policy", policy).Error; err != nil {
+		return nil, err
+	}
+	return s.postService.GetPost(userID, postID)
+}
+
+// HideComment hides a comment on the user's post from everyone but its author
+func (s *CommentModerationService) HideComment(userID, commentID uuid.UUID) (*models.CommentResponse, error) {
+	return s.setStatus(userID, commentID, constants.CommentStatusHidden)
+}
+
+// ApproveComment makes a hidden or held comment visible again
+func (s *CommentModerationService) ApproveComment(userID, commentID uuid.UUID) (*models.CommentResponse, error) {
+	return s.setStatus(userID, commentID, constants.CommentStatusVisible)
+}
+
+// PinComment pins a visible top level comment to the top of the user's post
+func (s *CommentModerationService) PinComment(userID, commentID uuid.UUID) (*models.CommentResponse, error) {
+	comment, post, err := s.findModeratedComment(userID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.ParentID != nil || comment.Status != constants.CommentStatusVisible {
+		return nil, apperrors.ErrCannotPinComment
+	}
+
+	// Pinning replaces any previously pinned comment
+	if err := s.db.Model(post).Update("pinned_comment_id", comment.ID).Error; err != nil {
+		return nil, err
+	}
+	return s.commentService.GetComment(userID, commentID)
+}
+
+// UnpinComment removes the pinned comment of the user's post
+func (s *CommentModerationService) UnpinComment(userID, commentID uuid.UUID) error {
+	_, post, err := s.findModeratedComment(userID, commentID)
+	if err != nil {
+		return err
+	}
+	if post.PinnedCommentID == nil || *post.PinnedCommentID != commentID {
+		return apperrors.ErrInvalidInput
+	}
+
+	return s.db.Model(post).Update("pinned_comment_id", nil).Error
+}
+
+// ListHeldComments lists hidden or held comments on the user's post, oldest first. As the
+// post author the user sees them regardless of who wrote them.
+func (s *CommentModerationService) ListHeldComments(userID, postID uuid.UUID, status string, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	if _, err := s.postService.findOwnedPost(userID, postID); err != nil {
+		return nil, err
+	}
+
+	query := s.db.Where("post_id = ? AND status = ?", postID, status)
+	return s.commentService.pageComments(userID, query, constants.CommentSortOldest, cursor, limit)
+}
+
+// GetFilterKeywords lists the user's comment filter keywords
+func (s *CommentModerationService) GetFilterKeywords(userID uuid.UUID) ([]models.CommentFilterKeyword, error) {
+	var keywords []models.CommentFilterKeyword
+	err := s.db.Where("user_id = ?", userID).Order("keyword ASC").Find(&keywords).Error
+	return keywords, err
+}
+
+// AddFilterKeyword adds a keyword that holds matching comments on the user's posts for review
+func (s *CommentModerationService) AddFilterKeyword(userID uuid.UUID, keyword string) (*models.CommentFilterKeyword, error) {
+	keyword = strings.ToLower(strings.TrimSpace(keyword))
+	if keyword == "" {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	entry := models.CommentFilterKeyword{UserID: userID, Keyword: keyword}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, userID); err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.CommentFilterKeyword{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= constants.MaxCommentFilterKeywords {
+			return apperrors.ErrKeywordLimitReached
+		}
+
+		if err := tx.Create(&entry).Error; err != nil {
+			if isUniqueViolation(err) {
+				return apperrors.ErrKeywordAlreadyExists
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// DeleteFilterKeyword removes one of the user's filter keywords
+func (s *CommentModerationService) DeleteFilterKeyword(userID, keywordID uuid.UUID) error {
+	result := s.db.Where("id = ? AND user_id = ?", keywordID, userID).Delete(&models.CommentFilterKeyword{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// setStatus moves a comment between visible and hidden states, keeping CommentsCount in step
+func (s *CommentModerationService) setStatus(userID, commentID uuid.UUID, status string) (*models.CommentResponse, error) {
+	comment, post, err := s.findModeratedComment(userID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Status == status {
+		return s.commentService.GetComment(userID, commentID)
+	}
+
+	delta := 0
+	switch {
+	case status == constants.CommentStatusVisible:
+		delta = 1
+	case comment.Status == constants.CommentStatusVisible:
+		delta = -1
+	}
+	// Mentions in held or hidden comments are announced once the comment becomes visible
+	shown := status == constants.CommentStatusVisible
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(comment).Update("status", status).Error; err != nil {
+			return err
+		}
+		if delta != 0 {
+			if err := tx.Model(post).
+				UpdateColumn("comments_count", gorm.Expr("GREATEST(comments_count + ?, 0)", delta)).Error; err != nil {
+				return err
+			}
+		}
+		if shown {
+			// Users notified while the comment was visible before are not notified again
+			var mentioned []uuid.UUID
+			if err := tx.Model(&models.Mention{}).
+				Where("comment_id = ?", comment.ID).
+				Where("NOT EXISTS (SELECT 1 FROM notifications n WHERE n.user_id = mentions.user_id AND n.comment_id = mentions.comment_id AND n.type = ?)",
+					constants.NotificationTypeMention).
+				Distinct().
+				Pluck("user_id", &mentioned).Error; err != nil {
+				return err
+			}
+			if err := s.commentService.notifyMentioned(tx, comment, mentioned); err != nil {
+				return err
+			}
+		}
+		if status != constants.CommentStatusVisible && post.PinnedCommentID != nil && *post.PinnedCommentID == comment.ID {
+			return tx.Model(post).Update("pinned_comment_id", nil).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.commentService.GetComment(userID, commentID)
+}
+
+// findModeratedComment loads a comment on a post owned by the user
+func (s *CommentModerationService) findModeratedComment(userID, commentID uuid.UUID) (*models.Comment, *models.Post, error) {
+	comment, err := s.commentService.findComment(commentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	post, err := s.postService.findOwnedPost(userID, comment.PostID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return comment, post, nil
+}
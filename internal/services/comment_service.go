@@ -0,0 +1,461 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/pkg/constants"
+	apperrors "social-media-backend/pkg/errors"
+	"social-media-backend/pkg/pagination"
+)
+
+type CommentService struct {
+	db                  *gorm.DB
+	postService         *PostService
+	notificationService *NotificationService
+}
+
+func NewCommentService(db *gorm.DB, postService *PostService, notificationService *NotificationService) *CommentService {
+	return &CommentService{db: db, postService: postService, notificationService: notificationService}
+}
+
+// CreateComment adds a comment or a reply. Replies to a comment at MaxCommentDepth are
+// attached to that comment's parent so threads never nest deeper than the cap.
+// Comments matching the post author's filter keywords are held for review.
+func (s *CommentService) CreateComment(userID uuid.UUID, req *models.CreateCommentRequest) (*models.CommentResponse, error) {
+	post, err := s.postService.GetPost(userID, req.PostID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := models.Comment{
+		PostID:  req.PostID,
+		UserID:  userID,
+		Content: req.Content,
+		Status:  constants.CommentStatusVisible,
+	}
+
+	if post.User.ID != userID {
+		switch post.CommentPolicy {
+		case constants.CommentPolicyOff:
+			return nil, apperrors.ErrCommentsDisabled
+		case constants.CommentPolicyFollowers:
+			following, err := s.postService.isFollowing(userID, post.User.ID)
+			if err != nil {
+				return nil, err
+			}
+			if !following {
+				return nil, apperrors.ErrCommentsRestricted
+			}
+		}
+
+		filtered, err := s.matchesFilter(post.User.ID, comment.Content)
+		if err != nil {
+			return nil, err
+		}
+		if filtered {
+			comment.Status = constants.CommentStatusPendingReview
+		}
+	}
+
+	var parent *models.Comment
+	if req.ParentID != nil {
+		if parent, err = s.findComment(*req.ParentID); err != nil {
+			return nil, err
+		}
+		if parent.PostID != req.PostID {
+			return nil, apperrors.ErrInvalidParentComment
+		}
+
+		if parent.Depth >= constants.MaxCommentDepth {
+			comment.ParentID = parent.ParentID
+			comment.Depth = parent.Depth
+		} else {
+			comment.ParentID = &parent.ID
+			comment.Depth = parent.Depth + 1
+		}
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+		mentioned, err := syncMentions(tx, mentionSource{CommentID: &comment.ID}, userID, comment.Content)
+		if err != nil {
+			return err
+		}
+		// Held comments are not counted and nobody is notified until they are approved
+		if comment.Status != constants.CommentStatusVisible {
+			return nil
+		}
+		if err := s.notifyMentioned(tx, &comment, mentioned); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Post{}).
+			Where("id = ?", comment.PostID).
+			UpdateColumn("comments_count", gorm.Expr("comments_count + 1")).Error; err != nil {
+			return err
+		}
+
+		if err := s.notificationService.Notify(tx, &models.Notification{
+			UserID:    post.User.ID,
+			ActorID:   userID,
+			Type:      constants.NotificationTypeComment,
+			PostID:    &comment.PostID,
+			CommentID: &comment.ID,
+			Content:   comment.Content,
+		}); err != nil {
+			return err
+		}
+
+		// The author of the replied comment hears about it too, unless they already got the post notification
+		if parent != nil && parent.UserID != post.User.ID {
+			return s.notificationService.Notify(tx, &models.Notification{
+				UserID:    parent.UserID,
+				ActorID:   userID,
+				Type:      constants.NotificationTypeComment,
+				PostID:    &comment.PostID,
+				CommentID: &comment.ID,
+				Content:   comment.Content,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetComment(userID, comment.ID)
+}
+
+// GetComment returns a single comment if the viewer can see its post. Hidden and held
+// comments are only visible to their author and the post author.
+func (s *CommentService) GetComment(viewerID, commentID uuid.UUID) (*models.CommentResponse, error) {
+	comment, err := s.findComment(commentID)
+	if err != nil {
+		return nil, err
+	}
+	post, err := s.postService.GetPost(viewerID, comment.PostID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Status != constants.CommentStatusVisible && comment.UserID != viewerID && post.User.ID != viewerID {
+		return nil, apperrors.ErrCommentNotFound
+	}
+
+	responses, err := s.buildCommentResponses(viewerID, []models.Comment{*comment})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// UpdateComment edits the content of the user's own comment
+func (s *CommentService) UpdateComment(userID, commentID uuid.UUID, req *models.UpdateCommentRequest) (*models.CommentResponse, error) {
+	comment, err := s.findComment(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID {
+		return nil, apperrors.ErrUnauthorizedAction
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(comment).Update("content", req.Content).Error; err != nil {
+			return err
+		}
+		mentioned, err := syncMentions(tx, mentionSource{CommentID: &comment.ID}, userID, comment.Content)
+		if err != nil {
+			return err
+		}
+		if comment.Status != constants.CommentStatusVisible {
+			return nil
+		}
+		return s.notifyMentioned(tx, comment, mentioned)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetComment(userID, commentID)
+}
+
+// DeleteComment soft deletes a comment together with its replies. Both the comment
+// author and the post author may delete it.
+func (s *CommentService) DeleteComment(userID, commentID uuid.UUID) error {
+	comment, err := s.findComment(commentID)
+	if err != nil {
+		return err
+	}
+	if comment.UserID != userID {
+		var post models.Post
+		if err := s.db.Select("id", "user_id").First(&post, "id = ?", comment.PostID).Error; err != nil {
+			return err
+		}
+		if post.UserID != userID {
+			return apperrors.ErrUnauthorizedAction
+		}
+	}
+
+	return s.deleteThread(comment)
+}
+
+// ListComments lists the top level comments of a post. The pinned comment leads the first page.
+func (s *CommentService) ListComments(viewerID, postID uuid.UUID, sort string, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	post, err := s.postService.GetPost(viewerID, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.db.Where("post_id = ? AND parent_id IS NULL", postID)
+	if post.PinnedCommentID != nil {
+		query = query.Where("id <> ?", *post.PinnedCommentID)
+	}
+
+	page, err := s.listComments(viewerID, query, sort, cursor, limit)
+	if err != nil || cursor != nil || post.PinnedCommentID == nil {
+		return page, err
+	}
+
+	pinned, err := s.GetComment(viewerID, *post.PinnedCommentID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrCommentNotFound) {
+			return page, nil
+		}
+		return nil, err
+	}
+	page.Items = append([]models.CommentResponse{*pinned}, page.Items.([]models.CommentResponse)...)
+	return page, nil
+}
+
+// ListReplies lists the direct replies to a comment. Replies to a hidden or held comment are
+// only reachable by those who can see the comment itself.
+func (s *CommentService) ListReplies(viewerID, commentID uuid.UUID, sort string, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	parent, err := s.findComment(commentID)
+	if err != nil {
+		return nil, err
+	}
+	post, err := s.postService.GetPost(viewerID, parent.PostID)
+	if err != nil {
+		return nil, err
+	}
+	if parent.Status != constants.CommentStatusVisible && parent.UserID != viewerID && post.User.ID != viewerID {
+		return nil, apperrors.ErrCommentNotFound
+	}
+
+	query := s.db.Where("parent_id = ?", commentID)
+	return s.listComments(viewerID, query, sort, cursor, limit)
+}
+
+// listComments pages through the comments of query the viewer can see: visible comments
+// plus their own, so authors of hidden comments do not notice they were hidden
+func (s *CommentService) listComments(viewerID uuid.UUID, query *gorm.DB, sort string, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	query = query.Where("(status = ? OR user_id = ?)", constants.CommentStatusVisible, viewerID)
+	return s.pageComments(viewerID, query, sort, cursor, limit)
+}
+
+// pageComments applies the sort mode and its keyset cursor to query, without any visibility
+// rules. Callers make sure the viewer may see every comment query matches.
+func (s *CommentService) pageComments(viewerID uuid.UUID, query *gorm.DB, sort string, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	switch sort {
+	case constants.CommentSortOldest:
+		if cursor != nil {
+			query = query.Where("(created_at, id) > (?, ?)", cursor.CreatedAt, cursor.ID)
+		}
+		query = query.Order("created_at ASC, id ASC")
+	case constants.CommentSortTop:
+		if cursor != nil {
+			query = query.Where("(likes_count, created_at, id) < (?, ?, ?)", cursor.Value, cursor.CreatedAt, cursor.ID)
+		}
+		query = query.Order("likes_count DESC, created_at DESC, id DESC")
+	default:
+		if cursor != nil {
+			query = query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+		}
+		query = query.Order("created_at DESC, id DESC")
+	}
+
+	var comments []models.Comment
+	if err := query.Preload("User").Limit(limit + 1).Find(&comments).Error; err != nil {
+		return nil, err
+	}
+
+	hasMore := len(comments) > limit
+	if hasMore {
+		comments = comments[:limit]
+	}
+
+	responses, err := s.buildCommentResponses(viewerID, comments)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &pagination.Page{Items: responses, HasMore: hasMore}
+	if hasMore {
+		last := comments[len(comments)-1]
+		page.NextCursor = pagination.NewRankedCursor(int64(last.LikesCount), last.CreatedAt, last.ID).Encode()
+	}
+	return page, nil
+}
+
+// deleteThread soft deletes a comment and all of its descendants and fixes the post counter,
+// which only counts visible comments
+func (s *CommentService) deleteThread(comment *models.Comment) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var thread []struct {
+			ID     uuid.UUID
+			Status string
+		}
+		if err := tx.Raw(`
+			WITH RECURSIVE thread AS (
+				SELECT id, status FROM comments WHERE id = ?
+				UNION ALL
+				SELECT c.id, c.status FROM comments c JOIN thread t ON c.parent_id = t.id WHERE c.deleted_at IS NULL
+			)
+			SELECT id, status FROM thread`, comment.ID).Scan(&thread).Error; err != nil {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(thread))
+		visible := 0
+		for i, row := range thread {
+			ids[i] = row.ID
+			if row.Status == constants.CommentStatusVisible {
+				visible++
+			}
+		}
+
+		if err := tx.Where("id IN ?", ids).Delete(&models.Comment{}).Error; err != nil {
+			return err
+		}
+		// A deleted pinned comment is unpinned
+		if err := tx.Model(&models.Post{}).
+			Where("id = ? AND pinned_comment_id IN ?", comment.PostID, ids).
+			Update("pinned_comment_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Post{}).
+			Where("id = ?", comment.PostID).
+			UpdateColumn("comments_count", gorm.Expr("GREATEST(comments_count - ?, 0)", visible)).Error
+	})
+}
+
+// matchesFilter reports whether content contains one of the post author's filter keywords
+func (s *CommentService) matchesFilter(postAuthorID uuid.UUID, content string) (bool, error) {
+	var keywords []string
+	if err := s.db.Model(&models.CommentFilterKeyword{}).
+		Where("user_id = ?", postAuthorID).
+		Pluck("keyword", &keywords).Error; err != nil {
+		return false, err
+	}
+
+	content = strings.ToLower(content)
+	for _, keyword := range keywords {
+		if strings.Contains(content, strings.ToLower(keyword)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// notifyMentioned tells users they were mentioned in a comment
+func (s *CommentService) notifyMentioned(tx *gorm.DB, comment *models.Comment, userIDs []uuid.UUID) error {
+	return s.notificationService.NotifyMany(tx, userIDs, models.Notification{
+		ActorID:   comment.UserID,
+		Type:      constants.NotificationTypeMention,
+		PostID:    &comment.PostID,
+		CommentID: &comment.ID,
+		Content:   comment.Content,
+	})
+}
+
+func (s *CommentService) findComment(commentID uuid.UUID) (*models.Comment, error) {
+	var comment models.Comment
+	if err := s.db.Preload("User").First(&comment, "id = ?", commentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// buildCommentResponses fills reply counts, viewer reactions and mentions in batch
+func (s *CommentService) buildCommentResponses(viewerID uuid.UUID, comments []models.Comment) ([]models.CommentResponse, error) {
+	responses := make([]models.CommentResponse, 0, len(comments))
+	if len(comments) == 0 {
+		return responses, nil
+	}
+
+	ids := make([]uuid.UUID, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+
+	postIDs := make([]uuid.UUID, 0, 1)
+	seenPosts := map[uuid.UUID]bool{}
+	for _, comment := range comments {
+		if !seenPosts[comment.PostID] {
+			seenPosts[comment.PostID] = true
+			postIDs = append(postIDs, comment.PostID)
+		}
+	}
+	var posts []models.Post
+	if err := s.db.Select("id", "user_id", "pinned_comment_id").Where("id IN ?", postIDs).Find(&posts).Error; err != nil {
+		return nil, err
+	}
+	postAuthors := make(map[uuid.UUID]uuid.UUID, len(posts))
+	pinned := map[uuid.UUID]bool{}
+	for _, post := range posts {
+		postAuthors[post.ID] = post.UserID
+		if post.PinnedCommentID != nil {
+			pinned[*post.PinnedCommentID] = true
+		}
+	}
+
+	var replyCounts []struct {
+		ParentID uuid.UUID
+		Count    int
+	}
+	if err := s.db.Model(&models.Comment{}).
+		Select("parent_id, COUNT(*) AS count").
+		Where("parent_id IN ? AND status = ?", ids, constants.CommentStatusVisible).
+		Group("parent_id").
+		Scan(&replyCounts).Error; err != nil {
+		return nil, err
+	}
+	replies := make(map[uuid.UUID]int, len(replyCounts))
+	for _, row := range replyCounts {
+		replies[row.ParentID] = row.Count
+	}
+
+	reactionCounts, viewerReactions, err := loadReactionSummaries(s.db, viewerID, ReactionTargetComment, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions, err := loadMentions(s.db, "comment_id", ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range comments {
+		comments[i].RepliesCount = replies[comments[i].ID]
+		comments[i].IsLiked = viewerReactions[comments[i].ID] != ""
+		response := comments[i].ToResponse()
+		response.IsPinned = pinned[comments[i].ID]
+		// Moderation state is only shown to the people involved
+		if viewerID != uuid.Nil && (comments[i].UserID == viewerID || postAuthors[comments[i].PostID] == viewerID) {
+			response.Status = comments[i].Status
+		}
+		response.ReactionCounts = reactionCounts[comments[i].ID]
+		response.ViewerReaction = viewerReactions[comments[i].ID]
+		response.Mentions = mentions[comments[i].ID]
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
@@ -0,0 +1,1032 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/storage"
+	"social-media-backend/pkg/constants"
+	apperrors "social-media-backend/pkg/errors"
+	"social-media-backend/pkg/pagination"
+)
+
+type ConversationService struct {
+	db           *gorm.DB
+	storage      storage.Storage
+	hub          *RealtimeHub
+	blockService *BlockService
+}
+
+func NewConversationService(db *gorm.DB, storage storage.Storage, hub *RealtimeHub, blockService *BlockService) *ConversationService {
+	return &ConversationService{db: db, storage: storage, hub: hub, blockService: blockService}
+}
+
+// CreateConversation opens the direct conversation with a single user, or creates a group
+// with the user as its first admin. Users who do not follow the creator find the
+// conversation in their message requests.
+func (s *ConversationService) CreateConversation(userID uuid.UUID, req *models.CreateConversationRequest) (*models.ConversationResponse, error) {
+	var memberIDs []uuid.UUID
+	for _, id := range req.UserIDs {
+		if id != userID && !slices.Contains(memberIDs, id) {
+			memberIDs = append(memberIDs, id)
+		}
+	}
+	if len(memberIDs) == 0 {
+		return nil, apperrors.ErrCannotMessageSelf
+	}
+	if len(memberIDs)+1 > constants.MaxGroupParticipants {
+		return nil, apperrors.ErrConversationFull
+	}
+	if err := checkCanAddUsers(s.db, userID, memberIDs); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	var conversation *models.Conversation
+	var announced []*models.Message
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if len(memberIDs) == 1 && name == "" {
+			conversation, err = findOrCreateDirectConversation(tx, userID, memberIDs[0])
+			return err
+		}
+
+		statuses, err := participantStatuses(tx, userID, memberIDs)
+		if err != nil {
+			return err
+		}
+
+		conversation = &models.Conversation{
+			Kind:        constants.ConversationKindGroup,
+			Name:        name,
+			CreatedByID: &userID,
+		}
+		if err := tx.Create(conversation).Error; err != nil {
+			return err
+		}
+
+		participants := []models.ConversationParticipant{{
+			ConversationID: conversation.ID,
+			UserID:         userID,
+			Role:           constants.ParticipantRoleAdmin,
+		}}
+		for _, id := range memberIDs {
+			participants = append(participants, models.ConversationParticipant{
+				ConversationID: conversation.ID,
+				UserID:         id,
+				Role:           constants.ParticipantRoleMember,
+				Status:         statuses[id],
+				AddedByID:      &userID,
+			})
+		}
+		if err := tx.Create(&participants).Error; err != nil {
+			return err
+		}
+
+		names, err := usernamesOf(tx, []uuid.UUID{userID})
+		if err != nil {
+			return err
+		}
+		message, err := createSystemMessage(tx, conversation.ID, userID, fmt.Sprintf("%s created the group", names[userID]))
+		if err != nil {
+			return err
+		}
+		announced = append(announced, message)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.hub.publishMessages(announced)
+
+	return s.GetConversation(userID, conversation.ID)
+}
+
+// GetConversations lists the user's inbox by latest message, each conversation with the last
+// message the user can see and the number of messages after their read cursor
+func (s *ConversationService) GetConversations(userID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	return s.listConversations(userID, constants.ParticipantStatusAccepted, cursor, limit)
+}
+
+// GetMessageRequests lists the conversations waiting in the user's message requests, like
+// GetConversations
+func (s *ConversationService) GetMessageRequests(userID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	return s.listConversations(userID, constants.ParticipantStatusPending, cursor, limit)
+}
+
+func (s *ConversationService) listConversations(userID uuid.UUID, status string, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	filter := "p.status = @status AND lm.id IS NOT NULL"
+	args := map[string]interface{}{"status": status, "limit": limit + 1}
+	if cursor != nil {
+		filter += " AND (lm.created_at, lm.id) < (@cursor_created_at, @cursor_id)"
+		args["cursor_created_at"] = cursor.CreatedAt
+		args["cursor_id"] = cursor.ID
+	}
+
+	summaries, err := conversationSummaries(s.db, userID, filter, args)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(summaries) > limit
+	if hasMore {
+		summaries = summaries[:limit]
+	}
+
+	responses, err := s.buildConversationResponses(userID, summaries)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &pagination.Page{Items: responses, HasMore: hasMore}
+	if hasMore {
+		last := summaries[len(summaries)-1]
+		page.NextCursor = pagination.NewCursor(*last.LastMessageAt, *last.LastMessageID).Encode()
+	}
+	return page, nil
+}
+
+// GetConversation returns a conversation of the user with all of its participants
+func (s *ConversationService) GetConversation(userID, conversationID uuid.UUID) (*models.ConversationResponse, error) {
+	summaries, err := conversationSummaries(s.db, userID, "p.conversation_id = @conversation_id", map[string]interface{}{
+		"conversation_id": conversationID,
+		"limit":           1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(summaries) == 0 {
+		return nil, apperrors.ErrConversationNotFound
+	}
+
+	responses, err := s.buildConversationResponses(userID, summaries)
+	if err != nil {
+		return nil, err
+	}
+	response := responses[0]
+
+	var participants []models.ConversationParticipant
+	if err := s.db.Preload("User").
+		Where("conversation_id = ?", conversationID).
+		Order("joined_at ASC, id ASC").
+		Find(&participants).Error; err != nil {
+		return nil, err
+	}
+	response.Participants = make([]models.ParticipantResponse, len(participants))
+	for i := range participants {
+		response.Participants[i] = participants[i].ToResponse()
+		// Read receipts stay hidden until the request is accepted
+		if participants[i].Status == constants.ParticipantStatusPending {
+			response.Participants[i].LastReadMessageID = nil
+			response.Participants[i].LastReadAt = nil
+		}
+	}
+	return &response, nil
+}
+
+// UpdateConversation renames a group. Only admins can change the group.
+func (s *ConversationService) UpdateConversation(userID, conversationID uuid.UUID, req *models.UpdateConversationRequest) (*models.ConversationResponse, error) {
+	var announced []*models.Message
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		conversation, err := lockGroupForAdmin(tx, conversationID, userID)
+		if err != nil {
+			return err
+		}
+		if req.Name == nil {
+			return nil
+		}
+
+		name := strings.TrimSpace(*req.Name)
+		if name == conversation.Name {
+			return nil
+		}
+		if err := tx.Model(conversation).Update("name", name).Error; err != nil {
+			return err
+		}
+
+		names, err := usernamesOf(tx, []uuid.UUID{userID})
+		if err != nil {
+			return err
+		}
+		content := fmt.Sprintf("%s removed the group name", names[userID])
+		if name != "" {
+			content = fmt.Sprintf("%s named the group %s", names[userID], name)
+		}
+		message, err := createSystemMessage(tx, conversation.ID, userID, content)
+		if err != nil {
+			return err
+		}
+		announced = append(announced, message)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.hub.publishMessages(announced)
+
+	return s.GetConversation(userID, conversationID)
+}
+
+// SetAvatar uploads a new group photo, replacing the previous one
+func (s *ConversationService) SetAvatar(ctx context.Context, userID, conversationID uuid.UUID, file *multipart.FileHeader) (*models.ConversationResponse, error) {
+	ext, err := validateMediaUpload(file, constants.PostTypeImage)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check permissions before storing anything
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		_, err := lockGroupForAdmin(tx, conversationID, userID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	key := fmt.Sprintf("conversations/%s/%s%s", conversationID, uuid.New(), ext)
+	url, err := s.storage.Save(ctx, key, src)
+	if err != nil {
+		log.Printf("conversation avatar upload: %v", err)
+		return nil, apperrors.ErrFileUploadFailed
+	}
+
+	var previousKey string
+	var announced []*models.Message
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		conversation, err := lockGroupForAdmin(tx, conversationID, userID)
+		if err != nil {
+			return err
+		}
+		previousKey = conversation.AvatarKey
+
+		if err := tx.Model(conversation).Updates(map[string]interface{}{
+			"avatar_url": url,
+			"avatar_key": key,
+		}).Error; err != nil {
+			return err
+		}
+
+		names, err := usernamesOf(tx, []uuid.UUID{userID})
+		if err != nil {
+			return err
+		}
+		message, err := createSystemMessage(tx, conversation.ID, userID, fmt.Sprintf("%s changed the group photo", names[userID]))
+		if err != nil {
+			return err
+		}
+		announced = append(announced, message)
+		return nil
+	})
+	if err != nil {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			log.Printf("conversation avatar cleanup: %v", err)
+		}
+		return nil, err
+	}
+	s.hub.publishMessages(announced)
+
+	if previousKey != "" {
+		if err := s.storage.Delete(ctx, previousKey); err != nil {
+			log.Printf("conversation avatar cleanup: %v", err)
+		}
+	}
+	return s.GetConversation(userID, conversationID)
+}
+
+// AddParticipants adds users to a group. They see the messages sent from now on; users who
+// do not follow the admin adding them find the group in their message requests.
+func (s *ConversationService) AddParticipants(userID, conversationID uuid.UUID, req *models.AddParticipantsRequest) (*models.ConversationResponse, error) {
+	var announced []*models.Message
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		conversation, err := lockGroupForAdmin(tx, conversationID, userID)
+		if err != nil {
+			return err
+		}
+
+		var existing []uuid.UUID
+		if err := tx.Model(&models.ConversationParticipant{}).
+			Where("conversation_id = ?", conversation.ID).
+			Pluck("user_id", &existing).Error; err != nil {
+			return err
+		}
+
+		var added []uuid.UUID
+		for _, id := range req.UserIDs {
+			if slices.Contains(existing, id) {
+				return apperrors.ErrAlreadyParticipant
+			}
+			if !slices.Contains(added, id) {
+				added = append(added, id)
+			}
+		}
+		if len(existing)+len(added) > constants.MaxGroupParticipants {
+			return apperrors.ErrConversationFull
+		}
+		if err := checkCanAddUsers(tx, userID, added); err != nil {
+			return err
+		}
+		statuses, err := participantStatuses(tx, userID, added)
+		if err != nil {
+			return err
+		}
+
+		participants := make([]models.ConversationParticipant, len(added))
+		for i, id := range added {
+			participants[i] = models.ConversationParticipant{
+				ConversationID: conversation.ID,
+				UserID:         id,
+				Role:           constants.ParticipantRoleMember,
+				Status:         statuses[id],
+				AddedByID:      &userID,
+			}
+		}
+		if err := tx.Create(&participants).Error; err != nil {
+			return err
+		}
+
+		names, err := usernamesOf(tx, append([]uuid.UUID{userID}, added...))
+		if err != nil {
+			return err
+		}
+		for _, id := range added {
+			content := fmt.Sprintf("%s added %s", names[userID], names[id])
+			message, err := createSystemMessage(tx, conversation.ID, userID, content)
+			if err != nil {
+				return err
+			}
+			announced = append(announced, message)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.hub.publishMessages(announced)
+
+	return s.GetConversation(userID, conversationID)
+}
+
+// RemoveParticipant removes another user from a group. Users leave on their own with
+// LeaveConversation.
+func (s *ConversationService) RemoveParticipant(userID, conversationID, targetID uuid.UUID) error {
+	if targetID == userID {
+		return s.LeaveConversation(userID, conversationID)
+	}
+
+	var announced []*models.Message
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		conversation, err := lockGroupForAdmin(tx, conversationID, userID)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Where("conversation_id = ? AND user_id = ?", conversation.ID, targetID).
+			Delete(&models.ConversationParticipant{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperrors.ErrNotParticipant
+		}
+
+		names, err := usernamesOf(tx, []uuid.UUID{userID, targetID})
+		if err != nil {
+			return err
+		}
+		message, err := createSystemMessage(tx, conversation.ID, userID, fmt.Sprintf("%s removed %s", names[userID], names[targetID]))
+		if err != nil {
+			return err
+		}
+		announced = append(announced, message)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.hub.publishMessages(announced)
+	return nil
+}
+
+// UpdateParticipantRole promotes a participant to admin or demotes them. A group always
+// keeps at least one admin.
+func (s *ConversationService) UpdateParticipantRole(userID, conversationID, targetID uuid.UUID, role string) error {
+	var announced []*models.Message
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		conversation, err := lockGroupForAdmin(tx, conversationID, userID)
+		if err != nil {
+			return err
+		}
+
+		var target models.ConversationParticipant
+		if err := tx.First(&target, "conversation_id = ? AND user_id = ?", conversation.ID, targetID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperrors.ErrNotParticipant
+			}
+			return err
+		}
+		if target.Role == role {
+			return nil
+		}
+
+		if role == constants.ParticipantRoleMember {
+			var admins int64
+			if err := tx.Model(&models.ConversationParticipant{}).
+				Where("conversation_id = ? AND role = ?", conversation.ID, constants.ParticipantRoleAdmin).
+				Count(&admins).Error; err != nil {
+				return err
+			}
+			if admins <= 1 {
+				return apperrors.ErrLastAdmin
+			}
+		}
+
+		if err := tx.Model(&target).Update("role", role).Error; err != nil {
+			return err
+		}
+
+		names, err := usernamesOf(tx, []uuid.UUID{userID, targetID})
+		if err != nil {
+			return err
+		}
+		content := fmt.Sprintf("%s made %s an admin", names[userID], names[targetID])
+		if role == constants.ParticipantRoleMember {
+			content = fmt.Sprintf("%s removed %s as an admin", names[userID], names[targetID])
+		}
+		message, err := createSystemMessage(tx, conversation.ID, userID, content)
+		if err != nil {
+			return err
+		}
+		announced = append(announced, message)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.hub.publishMessages(announced)
+	return nil
+}
+
+// LeaveConversation removes the user from a group. When the last admin leaves, the
+// longest standing participant who accepted the group becomes admin.
+func (s *ConversationService) LeaveConversation(userID, conversationID uuid.UUID) error {
+	var announced []*models.Message
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		conversation, participant, err := lockGroup(tx, conversationID, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(participant).Error; err != nil {
+			return err
+		}
+
+		names, err := usernamesOf(tx, []uuid.UUID{userID})
+		if err != nil {
+			return err
+		}
+		message, err := createSystemMessage(tx, conversation.ID, userID, fmt.Sprintf("%s left the group", names[userID]))
+		if err != nil {
+			return err
+		}
+		announced = append(announced, message)
+
+		if participant.Role != constants.ParticipantRoleAdmin {
+			return nil
+		}
+		var admins int64
+		if err := tx.Model(&models.ConversationParticipant{}).
+			Where("conversation_id = ? AND role = ?", conversation.ID, constants.ParticipantRoleAdmin).
+			Count(&admins).Error; err != nil {
+			return err
+		}
+		if admins > 0 {
+			return nil
+		}
+
+		// Members who never accepted the group only take it over when nobody else is left
+		for _, status := range []string{constants.ParticipantStatusAccepted, constants.ParticipantStatusPending} {
+			var successor models.ConversationParticipant
+			err = tx.Where("conversation_id = ? AND status = ?", conversation.ID, status).
+				Order("joined_at ASC, id ASC").
+				First(&successor).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			return tx.Model(&successor).Update("role", constants.ParticipantRoleAdmin).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.hub.publishMessages(announced)
+	return nil
+}
+
+// MarkRead moves the user's read cursor forward to a message, or to the latest message
+// of the conversation. The cursor never moves back. Other participants are only told once
+// the user accepted the conversation.
+func (s *ConversationService) MarkRead(userID, conversationID uuid.UUID, messageID *uuid.UUID) error {
+	participant, err := findParticipant(s.db, conversationID, userID)
+	if err != nil {
+		return err
+	}
+
+	query := s.db.Scopes(visibleMessagesScope(participant)).Where("conversation_id = ?", conversationID)
+	if messageID != nil {
+		query = query.Where("id = ?", *messageID)
+	}
+
+	var message models.Message
+	if err := query.Order("created_at DESC, id DESC").First(&message).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if messageID != nil {
+				return apperrors.ErrMessageNotFound
+			}
+			return nil
+		}
+		return err
+	}
+
+	readAt := time.Now()
+	moved, err := advanceReadCursor(s.db, conversationID, userID, &message, readAt)
+	if err != nil {
+		return err
+	}
+	if moved && participant.Status == constants.ParticipantStatusAccepted {
+		s.hub.publishReadReceipt(conversationID, userID, message.ID, readAt)
+	}
+	return nil
+}
+
+// SetTyping tells the other participants that the user started or stopped typing. Like read
+// receipts, typing stays private while the conversation is a message request.
+func (s *ConversationService) SetTyping(userID, conversationID uuid.UUID, typing bool) error {
+	participant, err := findParticipant(s.db, conversationID, userID)
+	if err != nil {
+		return err
+	}
+	if participant.Status == constants.ParticipantStatusPending {
+		return nil
+	}
+
+	s.hub.PublishToConversation(conversationID, userID, models.RealtimeEvent{
+		Type:           constants.RealtimeEventTyping,
+		ConversationID: &conversationID,
+		Data:           models.TypingIndicator{UserID: userID, Typing: typing},
+	})
+	return nil
+}
+
+// AcceptRequest moves a conversation from the user's message requests to their inbox and
+// reveals how far they have read
+func (s *ConversationService) AcceptRequest(userID, conversationID uuid.UUID) (*models.ConversationResponse, error) {
+	participant, err := findRequest(s.db, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(participant).Update("status", constants.ParticipantStatusAccepted).Error; err != nil {
+		return nil, err
+	}
+	if participant.LastReadMessageID != nil && participant.LastReadAt != nil {
+		s.hub.publishReadReceipt(conversationID, userID, *participant.LastReadMessageID, *participant.LastReadAt)
+	}
+
+	return s.GetConversation(userID, conversationID)
+}
+
+// DeclineRequest removes a conversation from the user's message requests. A declined direct
+// conversation is cleared without telling the sender and comes back as a new request if they
+// write again; a declined group is left.
+func (s *ConversationService) DeclineRequest(userID, conversationID uuid.UUID) error {
+	participant, err := findRequest(s.db, conversationID, userID)
+	if err != nil {
+		return err
+	}
+
+	var conversation models.Conversation
+	if err := s.db.Select("id", "kind").First(&conversation, "id = ?", conversationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrConversationNotFound
+		}
+		return err
+	}
+	if conversation.Kind == constants.ConversationKindGroup {
+		return s.LeaveConversation(userID, conversationID)
+	}
+
+	// Moving the join time hides everything sent so far
+	return s.db.Model(participant).Updates(map[string]interface{}{
+		"joined_at":            time.Now(),
+		"last_read_message_id": nil,
+		"last_read_message_at": nil,
+		"last_read_at":         nil,
+	}).Error
+}
+
+// BlockRequest blocks whoever sent the user a message request and declines it
+func (s *ConversationService) BlockRequest(userID, conversationID uuid.UUID) error {
+	participant, err := findRequest(s.db, conversationID, userID)
+	if err != nil {
+		return err
+	}
+	if participant.AddedByID == nil {
+		return apperrors.ErrNotMessageRequest
+	}
+
+	if err := s.blockService.BlockUser(userID, *participant.AddedByID); err != nil {
+		return err
+	}
+	return s.DeclineRequest(userID, conversationID)
+}
+
+// conversationSummary is a conversation as seen by one participant
+type conversationSummary struct {
+	ConversationID uuid.UUID
+	Role           string
+	Status         string
+	LastMessageID  *uuid.UUID
+	LastMessageAt  *time.Time
+	UnreadCount    int
+}
+
+// conversationSummaries finds the user's conversations matching filter, with the latest
+// message they can see and their unread count, latest first
+func conversationSummaries(db *gorm.DB, userID uuid.UUID, filter string, args map[string]interface{}) ([]conversationSummary, error) {
+	args["user"] = userID
+
+	var summaries []conversationSummary
+	err := db.Raw(fmt.Sprintf(`
+		SELECT p.conversation_id, p.role, p.status, lm.id AS last_message_id, lm.created_at AS last_message_at,
+			(SELECT COUNT(*) FROM messages m
+				WHERE m.conversation_id = p.conversation_id
+					AND m.deleted_at IS NULL
+					AND m.sender_id <> p.user_id
+					AND m.created_at > COALESCE(p.last_read_message_at, p.joined_at)
+					AND NOT EXISTS (SELECT 1 FROM hidden_messages h WHERE h.message_id = m.id AND h.user_id = p.user_id)
+			) AS unread_count
+		FROM conversation_participants p
+		LEFT JOIN LATERAL (
+			SELECT m.id, m.created_at FROM messages m
+			WHERE m.conversation_id = p.conversation_id
+				AND m.deleted_at IS NULL
+				AND m.created_at >= p.joined_at
+				AND NOT EXISTS (SELECT 1 FROM hidden_messages h WHERE h.message_id = m.id AND h.user_id = p.user_id)
+			ORDER BY m.created_at DESC, m.id DESC
+			LIMIT 1
+		) lm ON true
+		WHERE p.user_id = @user AND %s
+		ORDER BY lm.created_at DESC NULLS LAST, lm.id DESC
+		LIMIT @limit`, filter), args).Scan(&summaries).Error
+	return summaries, err
+}
+
+// buildConversationResponses renders conversations for the viewer in the order of summaries
+func (s *ConversationService) buildConversationResponses(viewerID uuid.UUID, summaries []conversationSummary) ([]models.ConversationResponse, error) {
+	responses := make([]models.ConversationResponse, 0, len(summaries))
+	if len(summaries) == 0 {
+		return responses, nil
+	}
+
+	ids := make([]uuid.UUID, len(summaries))
+	var messageIDs []uuid.UUID
+	for i, summary := range summaries {
+		ids[i] = summary.ConversationID
+		if summary.LastMessageID != nil {
+			messageIDs = append(messageIDs, *summary.LastMessageID)
+		}
+	}
+
+	var conversations []models.Conversation
+	if err := s.db.Where("id IN ?", ids).Find(&conversations).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]models.Conversation, len(conversations))
+	for _, conversation := range conversations {
+		byID[conversation.ID] = conversation
+	}
+
+	var counts []struct {
+		ConversationID    uuid.UUID
+		ParticipantsCount int
+	}
+	if err := s.db.Model(&models.ConversationParticipant{}).
+		Select("conversation_id, COUNT(*) AS participants_count").
+		Where("conversation_id IN ?", ids).
+		Group("conversation_id").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	participantCounts := make(map[uuid.UUID]int, len(counts))
+	for _, count := range counts {
+		participantCounts[count.ConversationID] = count.ParticipantsCount
+	}
+
+	// The other side of direct conversations
+	var others []models.ConversationParticipant
+	if err := s.db.Preload("User").
+		Joins("JOIN conversations c ON c.id = conversation_participants.conversation_id AND c.kind = ?", constants.ConversationKindDirect).
+		Where("conversation_participants.conversation_id IN ? AND conversation_participants.user_id <> ?", ids, viewerID).
+		Find(&others).Error; err != nil {
+		return nil, err
+	}
+	otherUsers := make(map[uuid.UUID]models.UserResponse, len(others))
+	for _, other := range others {
+		otherUsers[other.ConversationID] = other.User.ToResponse()
+	}
+
+	lastMessages := make(map[uuid.UUID]models.MessageResponse, len(messageIDs))
+	if len(messageIDs) > 0 {
+		var messages []models.Message
+		if err := s.db.Preload("Sender").Where("id IN ?", messageIDs).Find(&messages).Error; err != nil {
+			return nil, err
+		}
+		rendered, err := buildMessageResponses(s.db, viewerID, messages)
+		if err != nil {
+			return nil, err
+		}
+		for _, message := range rendered {
+			lastMessages[message.ConversationID] = message
+		}
+	}
+
+	for _, summary := range summaries {
+		conversation, ok := byID[summary.ConversationID]
+		if !ok {
+			continue
+		}
+		response := models.ConversationResponse{
+			ID:                conversation.ID,
+			Kind:              conversation.Kind,
+			Name:              conversation.Name,
+			AvatarURL:         conversation.AvatarURL,
+			Role:              summary.Role,
+			Status:            summary.Status,
+			ParticipantsCount: participantCounts[conversation.ID],
+			UnreadCount:       summary.UnreadCount,
+			CreatedAt:         conversation.CreatedAt,
+			UpdatedAt:         conversation.UpdatedAt,
+		}
+		if other, ok := otherUsers[conversation.ID]; ok {
+			response.User = &other
+		}
+		if message, ok := lastMessages[conversation.ID]; ok {
+			response.LastMessage = &message
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+// findOrCreateDirectConversation returns the direct conversation between two users, creating
+// it with both as participants the first time. A new conversation lands in the other user's
+// message requests unless they follow the user.
+func findOrCreateDirectConversation(tx *gorm.DB, userID, otherID uuid.UUID) (*models.Conversation, error) {
+	key := directKey(userID, otherID)
+	conversation := models.Conversation{
+		Kind:        constants.ConversationKindDirect,
+		DirectKey:   &key,
+		CreatedByID: &userID,
+	}
+	result := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "direct_key"}}, DoNothing: true}).
+		Create(&conversation)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		var existing models.Conversation
+		if err := tx.First(&existing, "direct_key = ?", key).Error; err != nil {
+			return nil, err
+		}
+		return &existing, nil
+	}
+
+	statuses, err := participantStatuses(tx, userID, []uuid.UUID{otherID})
+	if err != nil {
+		return nil, err
+	}
+	participants := []models.ConversationParticipant{
+		{ConversationID: conversation.ID, UserID: userID, Role: constants.ParticipantRoleMember},
+		{
+			ConversationID: conversation.ID,
+			UserID:         otherID,
+			Role:           constants.ParticipantRoleMember,
+			Status:         statuses[otherID],
+			AddedByID:      &userID,
+		},
+	}
+	if err := tx.Create(&participants).Error; err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+// directKey identifies the direct conversation of two users regardless of order
+func directKey(a, b uuid.UUID) string {
+	if strings.Compare(a.String(), b.String()) > 0 {
+		a, b = b, a
+	}
+	return a.String() + ":" + b.String()
+}
+
+// findParticipant loads the user's membership in a conversation. Conversations the user is
+// not part of are indistinguishable from missing ones.
+func findParticipant(db *gorm.DB, conversationID, userID uuid.UUID) (*models.ConversationParticipant, error) {
+	var participant models.ConversationParticipant
+	if err := db.First(&participant, "conversation_id = ? AND user_id = ?", conversationID, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrConversationNotFound
+		}
+		return nil, err
+	}
+	return &participant, nil
+}
+
+// findRequest loads the user's membership in a conversation that is still in their message
+// requests
+func findRequest(db *gorm.DB, conversationID, userID uuid.UUID) (*models.ConversationParticipant, error) {
+	participant, err := findParticipant(db, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if participant.Status != constants.ParticipantStatusPending {
+		return nil, apperrors.ErrNotMessageRequest
+	}
+	return participant, nil
+}
+
+// lockGroup locks a group conversation the user is part of, serializing membership changes
+func lockGroup(tx *gorm.DB, conversationID, userID uuid.UUID) (*models.Conversation, *models.ConversationParticipant, error) {
+	participant, err := findParticipant(tx, conversationID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var conversation models.Conversation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&conversation, "id = ?", conversationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, apperrors.ErrConversationNotFound
+		}
+		return nil, nil, err
+	}
+	if conversation.Kind != constants.ConversationKindGroup {
+		return nil, nil, apperrors.ErrNotGroupConversation
+	}
+	return &conversation, participant, nil
+}
+
+// lockGroupForAdmin locks a group conversation the user administers
+func lockGroupForAdmin(tx *gorm.DB, conversationID, userID uuid.UUID) (*models.Conversation, error) {
+	conversation, participant, err := lockGroup(tx, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if participant.Role != constants.ParticipantRoleAdmin {
+		return nil, apperrors.ErrUnauthorizedAction
+	}
+	return conversation, nil
+}
+
+// checkCanAddUsers makes sure all users exist and none of them blocked or was blocked by
+// the user adding them
+func checkCanAddUsers(db *gorm.DB, userID uuid.UUID, userIDs []uuid.UUID) error {
+	var count int64
+	if err := db.Model(&models.User{}).Where("id IN ?", userIDs).Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(userIDs) {
+		return apperrors.ErrUserNotFound
+	}
+
+	blocked, err := blockedAmong(db, userID, userIDs)
+	if err != nil {
+		return err
+	}
+	if len(blocked) > 0 {
+		return apperrors.ErrCannotMessageUser
+	}
+	return nil
+}
+
+// participantStatuses decides where a conversation actorID brings users into lands for each
+// of them: in the inbox of users who follow actorID, and in the message requests of anyone
+// else whose message policy lets actorID through. A user it does not let through fails the
+// whole conversation with ErrCannotMessageUser.
+func participantStatuses(db *gorm.DB, actorID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	var users []models.User
+	if err := db.Select("id", "message_policy").Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	var followerIDs []uuid.UUID
+	if err := db.Model(&models.Follow{}).
+		Where("following_id = ? AND follower_id IN ? AND status = ?", actorID, userIDs, constants.FollowStatusAccepted).
+		Pluck("follower_id", &followerIDs).Error; err != nil {
+		return nil, err
+	}
+	followsActor := make(map[uuid.UUID]bool, len(followerIDs))
+	for _, id := range followerIDs {
+		followsActor[id] = true
+	}
+
+	var followingIDs []uuid.UUID
+	if err := db.Model(&models.Follow{}).
+		Where("follower_id = ? AND following_id IN ? AND status = ?", actorID, userIDs, constants.FollowStatusAccepted).
+		Pluck("following_id", &followingIDs).Error; err != nil {
+		return nil, err
+	}
+	followedByActor := make(map[uuid.UUID]bool, len(followingIDs))
+	for _, id := range followingIDs {
+		followedByActor[id] = true
+	}
+
+	statuses := make(map[uuid.UUID]string, len(users))
+	for _, user := range users {
+		if followsActor[user.ID] {
+			statuses[user.ID] = constants.ParticipantStatusAccepted
+			continue
+		}
+		switch user.MessagePolicy {
+		case constants.MessagePolicyNobody:
+			return nil, apperrors.ErrCannotMessageUser
+		case constants.MessagePolicyFollowers:
+			if !followedByActor[user.ID] {
+				return nil, apperrors.ErrCannotMessageUser
+			}
+		}
+		statuses[user.ID] = constants.ParticipantStatusPending
+	}
+	return statuses, nil
+}
+
+// createSystemMessage records a membership or group change in the conversation
+func createSystemMessage(tx *gorm.DB, conversationID, actorID uuid.UUID, content string) (*models.Message, error) {
+	message := models.Message{
+		ConversationID: conversationID,
+		SenderID:       actorID,
+		Kind:           constants.MessageKindSystem,
+		Content:        content,
+	}
+	if err := tx.Create(&message).Error; err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// advanceReadCursor moves a participant's read cursor to message, read at readAt, unless it
+// is already past it. It reports whether the cursor moved.
+func advanceReadCursor(tx *gorm.DB, conversationID, userID uuid.UUID, message *models.Message, readAt time.Time) (bool, error) {
+	result := tx.Model(&models.ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+		Where("last_read_message_at IS NULL OR last_read_message_at < ?", message.CreatedAt).
+		Updates(map[string]interface{}{
+			"last_read_message_id": message.ID,
+			"last_read_message_at": message.CreatedAt,
+			"last_read_at":         readAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// visibleMessagesScope limits messages to those a participant can see: sent since they
+// joined and not deleted for them
+func visibleMessagesScope(participant *models.ConversationParticipant) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("messages.created_at >= ?", participant.JoinedAt).
+			Where("NOT EXISTS (SELECT 1 FROM hidden_messages h WHERE h.message_id = messages.id AND h.user_id = ?)", participant.UserID)
+	}
+}
+
+func usernamesOf(db *gorm.DB, userIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	var users []models.User
+	if err := db.Select("id", "username").Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	names := make(map[uuid.UUID]string, len(users))
+	for _, user := range users {
+		names[user.ID] = user.Username
+	}
+	return names, nil
+}
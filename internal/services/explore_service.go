@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/pkg/constants"
+	"social-media-backend/pkg/pagination"
+)
+
+type ExploreService struct {
+	db          *gorm.DB
+	rdb         *redis.Client
+	postService *PostService
+}
+
+func NewExploreService(db *gorm.DB, rdb *redis.Client, postService *PostService) *ExploreService {
+	return &ExploreService{db: db, rdb: rdb, postService: postService}
+}
+
+// GetExplore lists popular public posts for a region, at most one per author. region is
+// an ISO country code; when it is empty or invalid the viewer's own region is used, and
+// the global ranking when that is unknown too. The ranking is shared by everyone in the
+// region and cached for ExploreCacheTTL; blocked and muted authors are filtered out per
+// viewer, so a page may hold fewer posts than requested. Cursors belong to the ranking
+// they were handed out for and are rejected with ErrInvalidCursor once it was replaced.
+// When the ranking cannot be cached only its first page is served, without a cursor.
+func (s *ExploreService) GetExplore(ctx context.Context, viewerID uuid.UUID, region string, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	region, err := s.resolveRegion(viewerID, region)
+	if err != nil {
+		return nil, err
+	}
+
+	ranking, cached, err := s.ranking(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if cursor != nil {
+		if cursor.ID != ranking.ID {
+			return nil, pagination.ErrInvalidCursor
+		}
+		offset = int(cursor.Value)
+	}
+	if offset > len(ranking.PostIDs) {
+		offset = len(ranking.PostIDs)
+	}
+	end := offset + limit
+	if end > len(ranking.PostIDs) {
+		end = len(ranking.PostIDs)
+	}
+
+	posts, err := s.postService.loadPostsInOrder(ranking.PostIDs[offset:end],
+		visiblePostsScope(uuid.Nil), notBlockedScope(viewerID), notMutedScope(viewerID))
+	if err != nil {
+		return nil, err
+	}
+	responses, err := s.postService.buildPostResponses(viewerID, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &pagination.Page{Items: responses, HasMore: cached && end < len(ranking.PostIDs)}
+	if page.HasMore {
+		page.NextCursor = pagination.NewRankedCursor(int64(end), ranking.CreatedAt, ranking.ID).Encode()
+	}
+	return page, nil
+}
+
+// ranking returns the explore ranking of a region and whether it is cached, computing it
+// on a miss. Concurrent misses store only the first ranking and all return that one.
+func (s *ExploreService) ranking(ctx context.Context, region string) (*rankedSnapshot, bool, error) {
+	key := fmt.Sprintf(constants.CacheKeyExplore, region)
+	if ranking := s.cachedRanking(ctx, key); ranking != nil {
+		return ranking, true, nil
+	}
+
+	ids, err := s.rank(region, time.Now())
+	if err != nil {
+		return nil, false, err
+	}
+	ranking := &rankedSnapshot{ID: uuid.New(), CreatedAt: time.Now(), PostIDs: ids}
+	if s.rdb == nil {
+		return ranking, false, nil
+	}
+
+	payload, err := json.Marshal(ranking)
+	if err != nil {
+		return nil, false, err
+	}
+	stored, err := s.rdb.SetNX(ctx, key, payload, constants.ExploreCacheTTL).Result()
+	if err != nil {
+		log.Printf("explore cache: %v", err)
+		return ranking, false, nil
+	}
+	if !stored {
+		if winner := s.cachedRanking(ctx, key); winner != nil {
+			return winner, true, nil
+		}
+		return ranking, false, nil
+	}
+	return ranking, true, nil
+}
+
+// cachedRanking returns the ranking stored under key, or nil when there is none
+func (s *ExploreService) cachedRanking(ctx context.Context, key string) *rankedSnapshot {
+	if s.rdb == nil {
+		return nil
+	}
+
+	payload, err := s.rdb.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("explore cache: %v", err)
+		}
+		return nil
+	}
+	var ranking rankedSnapshot
+	if err := json.Unmarshal(payload, &ranking); err != nil {
+		return nil
+	}
+	return &ranking
+}
+
+// rank scores recent public posts of public accounts by engagement decayed with age,
+// boosts authors from the region, keeps the best post of each author and returns the
+// top ExploreCacheSize. Reposts are left out since their originals compete on their own.
+func (s *ExploreService) rank(region string, now time.Time) ([]uuid.UUID, error) {
+	var rows []struct {
+		ID uuid.UUID
+	}
+	if err := s.db.Raw(`
+		SELECT id FROM (
+			SELECT DISTINCT ON (posts.user_id) posts.id,
+				(posts.likes_count + 2 * posts.comments_count + 3 * posts.shares_count)
+					/ POWER(EXTRACT(EPOCH FROM (@now - posts.created_at)) / 3600 + 2, 1.5)
+					* CASE WHEN users.region = @region THEN @boost ELSE 1 END AS score
+			FROM posts
+			JOIN users ON users.id = posts.user_id
+			WHERE posts.deleted_at IS NULL AND users.deleted_at IS NULL
+				AND posts.is_public AND NOT posts.is_archived AND NOT users.is_private
+				AND COALESCE(posts.share_type, '') <> @repost
+				AND posts.created_at >= @since
+				AND posts.likes_count + 2 * posts.comments_count + 3 * posts.shares_count >= @min_engagement
+			ORDER BY posts.user_id, score DESC
+		) AS best
+		ORDER BY score DESC, id
+		LIMIT @limit`,
+		map[string]interface{}{
+			"now":            now,
+			"region":         region,
+			"boost":          constants.ExploreRegionBoost,
+			"repost":         constants.ShareTypeRepost,
+			"since":          now.Add(-constants.ExploreWindow),
+			"min_engagement": constants.ExploreMinEngagement,
+			"limit":          constants.ExploreCacheSize,
+		},
+	).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	return ids, nil
+}
+
+// resolveRegion validates the requested region and falls back to the viewer's region
+func (s *ExploreService) resolveRegion(viewerID uuid.UUID, region string) (string, error) {
+	if region = strings.ToUpper(region); isRegionCode(region) {
+		return region, nil
+	}
+
+	if viewerID != uuid.Nil {
+		var user models.User
+		if err := s.db.Select("region").First(&user, "id = ?", viewerID).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", err
+		}
+		if isRegionCode(user.Region) {
+			return user.Region, nil
+		}
+	}
+	return constants.ExploreRegionGlobal, nil
+}
+
+func isRegionCode(region string) bool {
+	if len(region) != 2 {
+		return false
+	}
+	for _, r := range region {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/pkg/constants"
+)
+
+// FeedFanout pushes new posts into the cached home feeds of the author's followers.
+// Posts of accounts with FanoutFollowerThreshold followers or more are not pushed, their
+// authors are recorded as celebrities and their posts are pulled when a feed is read.
+// Cached feeds are sorted sets of post IDs scored by creation time in microseconds.
+type FeedFanout struct {
+	db   *gorm.DB
+	rdb  *redis.Client
+	jobs chan fanoutJob
+}
+
+func NewFeedFanout(db *gorm.DB, rdb *redis.Client) *FeedFanout {
+	return &FeedFanout{db: db, rdb: rdb, jobs: make(chan fanoutJob, constants.FanoutQueueSize)}
+}
+
+// fanoutJob is a new post waiting to be pushed into cached feeds
+type fanoutJob struct {
+	authorID uuid.UUID
+	postID   uuid.UUID
+	score    float64
+}
+
+// pushFeedEntry adds a post to a cached feed and trims it, but only if the feed is cached:
+// a partial feed holding just the newest posts would hide everything older
+var pushFeedEntry = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+	redis.call('ZREMRANGEBYRANK', KEYS[1], 0, -tonumber(ARGV[3]) - 1)
+end
+return 0
+`)
+
+// Publish queues a newly created post for fanout by Run. Failures only cost freshness of
+// cached feeds until they expire, so they are logged rather than returned, and posts are
+// dropped rather than blocking the caller when the queue is full.
+func (f *FeedFanout) Publish(post *models.Post) {
+	if f == nil || f.rdb == nil {
+		return
+	}
+
+	select {
+	case f.jobs <- fanoutJob{authorID: post.UserID, postID: post.ID, score: feedScore(post)}:
+	default:
+		log.Printf("feed fanout: queue full, dropping post %s", post.ID)
+	}
+}
+
+// Run fans queued posts out with FanoutWorkers workers until ctx is done
+func (f *FeedFanout) Run(ctx context.Context) {
+	if f.rdb == nil {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < constants.FanoutWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-f.jobs:
+					if err := f.publish(ctx, job.authorID, job.postID, job.score); err != nil {
+						log.Printf("feed fanout: post %s: %v", job.postID, err)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Invalidate drops the cached feeds of users whose followed accounts changed. They are
+// rebuilt from Postgres on the next read.
+func (f *FeedFanout) Invalidate(ctx context.Context, userIDs ...uuid.UUID) {
+	if f == nil || f.rdb == nil || len(userIDs) == 0 {
+		return
+	}
+
+	keys := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		keys[i] = feedKey(id)
+	}
+	if err := f.rdb.Del(ctx, keys...).Err(); err != nil {
+		log.Printf("feed cache: invalidate: %v", err)
+	}
+}
+
+func (f *FeedFanout) publish(ctx context.Context, authorID, postID uuid.UUID, score float64) error {
+	// Counting stops at the threshold, so celebrities cost no more than anyone else here
+	var followers int64
+	if err := f.db.Raw(
+		"SELECT COUNT(*) FROM (SELECT 1 FROM follows WHERE following_id = ? AND status = ? LIMIT ?) AS f",
+		authorID, constants.FollowStatusAccepted, constants.FanoutFollowerThreshold,
+	).Scan(&followers).Error; err != nil {
+		return err
+	}
+	if followers >= constants.FanoutFollowerThreshold {
+		return f.rdb.SAdd(ctx, constants.CacheKeyCelebrities, authorID.String()).Err()
+	}
+
+	var followerIDs []uuid.UUID
+	if err := f.db.Model(&models.Follow{}).
+		Where("following_id = ? AND status = ?", authorID, constants.FollowStatusAccepted).
+		Pluck("follower_id", &followerIDs).Error; err != nil {
+		return err
+	}
+
+	pipe := f.rdb.Pipeline()
+	for _, followerID := range append(followerIDs, authorID) {
+		pushFeedEntry.Eval(ctx, pipe, []string{feedKey(followerID)}, score, postID.String(), constants.FeedCacheSize)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// celebritiesAmong reports which of userIDs are fanned out on read
+func (f *FeedFanout) celebritiesAmong(ctx context.Context, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	members := make([]interface{}, len(userIDs))
+	for i, id := range userIDs {
+		members[i] = id.String()
+	}
+	flags, err := f.rdb.SMIsMember(ctx, constants.CacheKeyCelebrities, members...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var celebrities []uuid.UUID
+	for i, isCelebrity := range flags {
+		if isCelebrity {
+			celebrities = append(celebrities, userIDs[i])
+		}
+	}
+	return celebrities, nil
+}
+
+// warm rebuilds a cached feed from Postgres with the latest posts of the followed accounts.
+// The feed expires FeedCacheTTL after it was built; reads do not extend it, so feeds that
+// missed a change catch up eventually even for users who never go idle.
+func (f *FeedFanout) warm(ctx context.Context, userID uuid.UUID) error {
+	var posts []models.Post
+	if err := f.db.Select("id", "created_at").
+		Where("user_id = ? OR user_id IN (SELECT following_id FROM follows WHERE follower_id = ? AND status = ?)",
+			userID, userID, constants.FollowStatusAccepted).
+		Order("created_at DESC, id DESC").
+		Limit(constants.FeedCacheSize).
+		Find(&posts).Error; err != nil {
+		return err
+	}
+	if len(posts) == 0 {
+		return nil
+	}
+
+	entries := make([]redis.Z, len(posts))
+	for i := range posts {
+		entries[i] = redis.Z{Score: feedScore(&posts[i]), Member: posts[i].ID.String()}
+	}
+
+	key := feedKey(userID)
+	pipe := f.rdb.TxPipeline()
+	pipe.ZAdd(ctx, key, entries...)
+	pipe.Expire(ctx, key, constants.FeedCacheTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func feedKey(userID uuid.UUID) string {
+	return fmt.Sprintf(constants.CacheKeyUserFeed, userID)
+}
+
+func feedScore(post *models.Post) float64 {
+	return float64(post.CreatedAt.UnixMicro())
+}
@@ -0,0 +1,429 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/pkg/constants"
+	"social-media-backend/pkg/pagination"
+)
+
+// followedHashtagPosts selects the IDs of posts tagged with a hashtag the user follows
+const followedHashtagPosts = `SELECT post_hashtags.post_id FROM post_hashtags
+	JOIN hashtag_follows ON hashtag_follows.hashtag_id = post_hashtags.hashtag_id
+	WHERE hashtag_follows.user_id = ?`
+
+type FeedService struct {
+	db          *gorm.DB
+	rdb         *redis.Client
+	postService *PostService
+	fanout      *FeedFanout
+	scorer      Scorer
+}
+
+func NewFeedService(db *gorm.DB, rdb *redis.Client, postService *PostService, fanout *FeedFanout, scorer Scorer) *FeedService {
+	return &FeedService{db: db, rdb: rdb, postService: postService, fanout: fanout, scorer: scorer}
+}
+
+// GetHomeFeed lists the viewer's own posts, posts of the accounts they follow and recent
+// public posts tagged with hashtags they follow, newest first. Each post appears once even
+// when it matches more than one source.
+//
+// Posts of ordinary accounts come from the viewer's cached feed, filled on write by
+// FeedFanout, and are merged with the posts of followed celebrities, the viewer's own posts
+// and hashtag posts queried at read time. The whole feed is queried from Postgres when
+// Redis is unavailable, the feed is not cached yet or the page reaches past the cache.
+func (s *FeedService) GetHomeFeed(ctx context.Context, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	if s.rdb != nil {
+		page, err := s.cachedHomeFeed(ctx, viewerID, cursor, limit)
+		if err != nil {
+			log.Printf("feed cache: %v", err)
+		}
+		if page != nil {
+			return page, nil
+		}
+	}
+
+	query := s.db.Model(&models.Post{}).Scopes(s.homeFeedScopes(viewerID, cursor)...)
+
+	var posts []models.Post
+	if err := query.Preload("User").
+		Order("posts.created_at DESC, posts.id DESC").
+		Limit(limit + 1).
+		Find(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	return s.postService.buildPostPage(viewerID, posts, limit)
+}
+
+// cachedHomeFeed builds a page from the cached feed. It returns a nil page when the cache
+// cannot serve it and the caller should query Postgres instead.
+func (s *FeedService) cachedHomeFeed(ctx context.Context, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	key := feedKey(viewerID)
+	max := "+inf"
+	if cursor != nil {
+		max = strconv.FormatInt(cursor.CreatedAt.UnixMicro(), 10)
+	}
+
+	// Entries the viewer can no longer see are filtered out below, so read a few extra
+	count := int64(limit*constants.FeedOverfetch + 1)
+	members, err := s.rdb.ZRevRangeByScore(ctx, key, &redis.ZRangeBy{Min: "-inf", Max: max, Count: count}).Result()
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(members)) < count {
+		size, err := s.rdb.ZCard(ctx, key).Result()
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			go func() {
+				if err := s.fanout.warm(context.Background(), viewerID); err != nil {
+					log.Printf("feed cache: warm %s: %v", viewerID, err)
+				}
+			}()
+			return nil, nil
+		}
+		// A full cache may have trimmed the posts this page needs
+		if size >= constants.FeedCacheSize {
+			return nil, nil
+		}
+	}
+	candidates := make([]uuid.UUID, 0, len(members)+limit+1)
+	for _, member := range members {
+		if id, err := uuid.Parse(member); err == nil {
+			candidates = append(candidates, id)
+		}
+	}
+
+	var followingIDs []uuid.UUID
+	if err := s.db.Model(&models.Follow{}).
+		Where("follower_id = ? AND status = ?", viewerID, constants.FollowStatusAccepted).
+		Pluck("following_id", &followingIDs).Error; err != nil {
+		return nil, err
+	}
+	celebrities, err := s.fanout.celebritiesAmong(ctx, followingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var pulled []uuid.UUID
+	if err := s.db.Model(&models.Post{}).
+		Scopes(s.homeFeedScopes(viewerID, cursor)...).
+		Where(`(posts.user_id = ? OR posts.user_id IN ? OR posts.id IN (`+followedHashtagPosts+`))`,
+			viewerID, celebrities, viewerID).
+		Order("posts.created_at DESC, posts.id DESC").
+		Limit(limit+1).
+		Pluck("posts.id", &pulled).Error; err != nil {
+		return nil, err
+	}
+	candidates = append(candidates, pulled...)
+
+	var posts []models.Post
+	if err := s.db.Model(&models.Post{}).
+		Scopes(s.homeFeedScopes(viewerID, cursor)...).
+		Where("posts.id IN ?", candidates).
+		Preload("User").
+		Order("posts.created_at DESC, posts.id DESC").
+		Limit(limit + 1).
+		Find(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	// Too many cached entries were filtered out to tell whether more posts follow
+	if len(posts) <= limit && int64(len(members)) == count {
+		return nil, nil
+	}
+
+	return s.postService.buildPostPage(viewerID, posts, limit)
+}
+
+// homeFeedScopes selects the visible posts of the viewer's home feed before the cursor
+func (s *FeedService) homeFeedScopes(viewerID uuid.UUID, cursor *pagination.Cursor) []func(*gorm.DB) *gorm.DB {
+	scopes := []func(*gorm.DB) *gorm.DB{
+		visiblePostsScope(viewerID),
+		notBlockedScope(viewerID),
+		notMutedScope(viewerID),
+		homeFeedScope(viewerID, time.Now().Add(-constants.HashtagFeedWindow)),
+	}
+	if cursor != nil {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("(posts.created_at, posts.id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+		})
+	}
+	return scopes
+}
+
+// homeFeedScope restricts posts to the sources of the viewer's home feed. It only selects
+// sources; visiblePostsScope still decides whether each post may be shown.
+func homeFeedScope(viewerID uuid.UUID, hashtagsSince time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("posts.is_archived = ?", false).Where(
+			`(posts.user_id = ?
+			OR posts.user_id IN (SELECT following_id FROM follows WHERE follower_id = ? AND status = ?)
+			OR (posts.created_at >= ? AND posts.id IN (`+followedHashtagPosts+`)))`,
+			viewerID, viewerID, constants.FollowStatusAccepted, hashtagsSince, viewerID,
+		)
+	}
+}
+
+// errCacheUnavailable reports a ranking that could not be cached because Redis is not set up
+var errCacheUnavailable = errors.New("cache unavailable")
+
+// rankedSnapshot is a ranked feed frozen for RankedFeedTTL so that paging through it
+// neither repeats nor skips posts while scores change
+type rankedSnapshot struct {
+	ID        uuid.UUID   `json:"id"`
+	CreatedAt time.Time   `json:"created_at"`
+	PostIDs   []uuid.UUID `json:"post_ids"`
+}
+
+// GetRankedFeed lists posts of followed accounts, followed hashtags and popular public
+// posts ordered by the scorer, with no author appearing more than
+// MaxConsecutivePostsPerAuthor times in a row. The first page ranks the candidates and
+// later pages continue from that ranking while it is cached. Once it expired, its cursors
+// are rejected with ErrInvalidCursor and clients start over from the first page.
+//
+// When the ranking cannot be cached the chronological feed is served instead, whose keyset
+// cursors are told apart from ranked ones by their zero Value.
+func (s *FeedService) GetRankedFeed(ctx context.Context, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	if cursor != nil && cursor.Value == 0 {
+		return s.GetHomeFeed(ctx, viewerID, cursor, limit)
+	}
+
+	var snapshot *rankedSnapshot
+	offset := 0
+	if cursor != nil {
+		offset = int(cursor.Value)
+		snapshot = s.cachedSnapshot(ctx, viewerID, cursor.ID)
+		// The offset only means something within the ranking it was handed out for
+		if snapshot == nil {
+			return nil, pagination.ErrInvalidCursor
+		}
+	}
+	if snapshot == nil {
+		ids, err := s.rankFeed(viewerID, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		snapshot = &rankedSnapshot{ID: uuid.New(), CreatedAt: time.Now(), PostIDs: ids}
+		// A cursor into a ranking that was never stored could not be followed
+		if err := s.storeSnapshot(ctx, viewerID, snapshot); err != nil {
+			log.Printf("feed cache: %v", err)
+			return s.GetHomeFeed(ctx, viewerID, nil, limit)
+		}
+	}
+
+	if offset > len(snapshot.PostIDs) {
+		offset = len(snapshot.PostIDs)
+	}
+	end := offset + limit
+	if end > len(snapshot.PostIDs) {
+		end = len(snapshot.PostIDs)
+	}
+
+	// Posts deleted or hidden since the ranking are left out of the page
+	posts, err := s.postService.loadPostsInOrder(snapshot.PostIDs[offset:end],
+		visiblePostsScope(viewerID), notBlockedScope(viewerID), notMutedScope(viewerID))
+	if err != nil {
+		return nil, err
+	}
+	responses, err := s.postService.buildPostResponses(viewerID, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &pagination.Page{Items: responses, HasMore: end < len(snapshot.PostIDs)}
+	if page.HasMore {
+		page.NextCursor = pagination.NewRankedCursor(int64(end), snapshot.CreatedAt, snapshot.ID).Encode()
+	}
+	return page, nil
+}
+
+// rankFeed gathers the candidates of the viewer's ranked feed and returns their IDs in
+// ranked order
+func (s *FeedService) rankFeed(viewerID uuid.UUID, now time.Time) ([]uuid.UUID, error) {
+	candidates, err := s.rankingCandidates(viewerID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[uuid.UUID]float64, len(candidates))
+	for _, candidate := range candidates {
+		scores[candidate.Post.ID] = s.scorer.Score(candidate)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return scores[candidates[i].Post.ID] > scores[candidates[j].Post.ID]
+	})
+	candidates = applyDiversity(candidates, constants.MaxConsecutivePostsPerAuthor)
+
+	ids := make([]uuid.UUID, len(candidates))
+	for i, candidate := range candidates {
+		ids[i] = candidate.Post.ID
+	}
+	return ids, nil
+}
+
+// rankingCandidates collects recent posts of followed accounts and followed hashtags and
+// popular public posts, each post once, with the viewer's affinity to its author
+func (s *FeedService) rankingCandidates(viewerID uuid.UUID, now time.Time) ([]*RankingCandidate, error) {
+	recent := now.Add(-constants.RankingCandidateWindow)
+	base := func() *gorm.DB {
+		return s.db.Model(&models.Post{}).
+			Scopes(visiblePostsScope(viewerID), notBlockedScope(viewerID), notMutedScope(viewerID)).
+			Where("posts.is_archived = ? AND posts.user_id <> ?", false, viewerID)
+	}
+
+	sources := []struct {
+		name  string
+		query *gorm.DB
+		limit int
+	}{
+		{
+			name: CandidateFollowed,
+			query: base().
+				Where("posts.created_at >= ?", recent).
+				Where("posts.user_id IN (SELECT following_id FROM follows WHERE follower_id = ? AND status = ?)",
+					viewerID, constants.FollowStatusAccepted).
+				Order("posts.created_at DESC"),
+			limit: constants.RankingFollowedCandidates,
+		},
+		{
+			name: CandidateHashtag,
+			query: base().
+				Where("posts.created_at >= ?", recent).
+				Where("posts.id IN ("+followedHashtagPosts+")", viewerID).
+				Order("posts.created_at DESC"),
+			limit: constants.RankingHashtagCandidates,
+		},
+		{
+			name: CandidatePopular,
+			query: base().
+				Scopes(visiblePostsScope(uuid.Nil)).
+				Where("posts.created_at >= ?", now.Add(-constants.RankingPopularWindow)).
+				Order("posts.likes_count + 2 * posts.comments_count + 3 * posts.shares_count DESC"),
+			limit: constants.RankingPopularCandidates,
+		},
+	}
+
+	var candidates []*RankingCandidate
+	seen := map[uuid.UUID]bool{}
+	for _, source := range sources {
+		var posts []models.Post
+		if err := source.query.Limit(source.limit).Find(&posts).Error; err != nil {
+			return nil, err
+		}
+		for i := range posts {
+			if seen[posts[i].ID] {
+				continue
+			}
+			seen[posts[i].ID] = true
+			candidates = append(candidates, &RankingCandidate{Post: &posts[i], Source: source.name, Now: now})
+		}
+	}
+
+	authorIDs := make([]uuid.UUID, 0, len(candidates))
+	for _, candidate := range candidates {
+		authorIDs = append(authorIDs, candidate.Post.UserID)
+	}
+	affinities, err := s.authorAffinities(viewerID, authorIDs, now.Add(-constants.RankingAffinityWindow))
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range candidates {
+		candidate.Affinity = affinities[candidate.Post.UserID]
+	}
+	return candidates, nil
+}
+
+// authorAffinities scores how close the viewer is to each author: ln(1 + likes + 2 *
+// comments) the viewer gave the author's posts since the given time, plus one when the
+// viewer follows the author
+func (s *FeedService) authorAffinities(viewerID uuid.UUID, authorIDs []uuid.UUID, since time.Time) (map[uuid.UUID]float64, error) {
+	affinities := map[uuid.UUID]float64{}
+	if len(authorIDs) == 0 {
+		return affinities, nil
+	}
+
+	var rows []struct {
+		AuthorID     uuid.UUID
+		Interactions float64
+	}
+	if err := s.db.Raw(`
+		SELECT author_id, SUM(weight) AS interactions FROM (
+			SELECT posts.user_id AS author_id, 1 AS weight FROM likes
+			JOIN posts ON posts.id = likes.post_id
+			WHERE likes.user_id = @viewer AND likes.created_at >= @since AND posts.user_id IN @authors
+			UNION ALL
+			SELECT posts.user_id AS author_id, 2 AS weight FROM comments
+			JOIN posts ON posts.id = comments.post_id
+			WHERE comments.user_id = @viewer AND comments.deleted_at IS NULL AND comments.created_at >= @since AND posts.user_id IN @authors
+		) AS interactions
+		GROUP BY author_id`,
+		map[string]interface{}{"viewer": viewerID, "since": since, "authors": authorIDs},
+	).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		affinities[row.AuthorID] = math.Log1p(row.Interactions)
+	}
+
+	var followingIDs []uuid.UUID
+	if err := s.db.Model(&models.Follow{}).
+		Where("follower_id = ? AND following_id IN ? AND status = ?", viewerID, authorIDs, constants.FollowStatusAccepted).
+		Pluck("following_id", &followingIDs).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range followingIDs {
+		affinities[id]++
+	}
+	return affinities, nil
+}
+
+func (s *FeedService) cachedSnapshot(ctx context.Context, viewerID, snapshotID uuid.UUID) *rankedSnapshot {
+	if s.rdb == nil {
+		return nil
+	}
+
+	payload, err := s.rdb.Get(ctx, fmt.Sprintf(constants.CacheKeyRankedFeed, viewerID, snapshotID)).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			log.Printf("feed cache: %v", err)
+		}
+		return nil
+	}
+
+	var snapshot rankedSnapshot
+	if err := json.Unmarshal(payload, &snapshot); err != nil || snapshot.ID != snapshotID {
+		return nil
+	}
+	return &snapshot
+}
+
+// storeSnapshot caches a ranking under its own ID, so every session of the viewer pages
+// through the ranking it started with
+func (s *FeedService) storeSnapshot(ctx context.Context, viewerID uuid.UUID, snapshot *rankedSnapshot) error {
+	if s.rdb == nil {
+		return errCacheUnavailable
+	}
+
+	payload, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf(constants.CacheKeyRankedFeed, viewerID, snapshot.ID)
+	return s.rdb.Set(ctx, key, payload, constants.RankedFeedTTL).Err()
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/pkg/constants"
+	apperrors "social-media-backend/pkg/errors"
+	"social-media-backend/pkg/pagination"
+)
+
+type FollowService struct {
+	db *gorm.DB
+}
+
+func NewFollowService(db *gorm.DB) *FollowService {
+	return &FollowService{db: db}
+}
+
+// GetFollowers lists the accepted followers of a user, most recent first
+func (s *FollowService) GetFollowers(viewerID, userID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	return s.listFollows(viewerID, userID, "following_id", "Follower", cursor, limit)
+}
+
+// GetFollowing lists the users a user follows, most recent first
+func (s *FollowService) GetFollowing(viewerID, userID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	return s.listFollows(viewerID, userID, "follower_id", "Following", cursor, limit)
+}
+
+// listFollows pages through the accepted follows where column is userID and renders the
+// other side of each relationship. Connections of private accounts are only visible to the
+// owner and their followers.
+func (s *FollowService) listFollows(viewerID, userID uuid.UUID, column, relation string, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	if err := s.checkVisible(viewerID, userID); err != nil {
+		return nil, err
+	}
+
+	query := s.db.Preload(relation).
+		Where(column+" = ? AND status = ?", userID, constants.FollowStatusAccepted)
+	if cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	var follows []models.Follow
+	if err := query.Order("created_at DESC, id DESC").
+		Limit(limit + 1).
+		Find(&follows).Error; err != nil {
+		return nil, err
+	}
+
+	hasMore := len(follows) > limit
+	if hasMore {
+		follows = follows[:limit]
+	}
+
+	users := make([]models.UserResponse, len(follows))
+	for i, follow := range follows {
+		if relation == "Follower" {
+			users[i] = follow.Follower.ToResponse()
+		} else {
+			users[i] = follow.Following.ToResponse()
+		}
+	}
+
+	page := &pagination.Page{Items: users, HasMore: hasMore}
+	if hasMore {
+		last := follows[len(follows)-1]
+		page.NextCursor = pagination.NewCursor(last.CreatedAt, last.ID).Encode()
+	}
+	return page, nil
+}
+
+func (s *FollowService) checkVisible(viewerID, userID uuid.UUID) error {
+	var user models.User
+	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrUserNotFound
+		}
+		return err
+	}
+	if !user.IsPrivate || viewerID == userID {
+		return nil
+	}
+
+	if viewerID != uuid.Nil {
+		var count int64
+		if err := s.db.Model(&models.Follow{}).
+			Where("follower_id = ? AND following_id = ? AND status = ?", viewerID, userID, constants.FollowStatusAccepted).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+	}
+	return apperrors.ErrPrivateAccount
+}
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/utils"
+	apperrors "social-media-backend/pkg/errors"
+	"social-media-backend/pkg/pagination"
+)
+
+type HashtagService struct {
+	db          *gorm.DB
+	postService *PostService
+}
+
+func NewHashtagService(db *gorm.DB, postService *PostService) *HashtagService {
+	return &HashtagService{db: db, postService: postService}
+}
+
+// postHashtag is a row of the post_hashtags join table
+type postHashtag struct {
+	PostID    uuid.UUID
+	HashtagID uuid.UUID
+}
+
+func (postHashtag) TableName() string {
+	return "post_hashtags"
+}
+
+// GetHashtag looks a hashtag up by name, with or without the leading '#'
+func (s *HashtagService) GetHashtag(viewerID uuid.UUID, name string) (*models.HashtagResponse, error) {
+	hashtag, err := s.findHashtag(name)
+	if err != nil {
+		return nil, err
+	}
+
+	response := hashtag.ToResponse()
+	if viewerID != uuid.Nil {
+		var count int64
+		if err := s.db.Model(&models.HashtagFollow{}).
+			Where("user_id = ? AND hashtag_id = ?", viewerID, hashtag.ID).
+			Count(&count).Error; err != nil {
+			return nil, err
+		}
+		response.IsFollowing = count > 0
+	}
+	return &response, nil
+}
+
+// FollowHashtag subscribes the user to a hashtag, creating it if nobody has used it yet.
+// Following twice is a no-op.
+func (s *HashtagService) FollowHashtag(userID uuid.UUID, name string) (*models.HashtagResponse, error) {
+	normalized, ok := utils.NormalizeHashtag(name)
+	if !ok {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		ids, err := upsertHashtags(tx, []string{normalized})
+		if err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.HashtagFollow{UserID: userID, HashtagID: ids[0]}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetHashtag(userID, normalized)
+}
+
+// UnfollowHashtag removes the user's subscription to a hashtag. Unfollowing twice is a no-op.
+func (s *HashtagService) UnfollowHashtag(userID uuid.UUID, name string) error {
+	hashtag, err := s.findHashtag(name)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Where("user_id = ? AND hashtag_id = ?", userID, hashtag.ID).
+		Delete(&models.HashtagFollow{}).Error
+}
+
+// GetFollowedHashtags lists the hashtags the user follows, most recently followed first
+func (s *HashtagService) GetFollowedHashtags(userID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	query := s.db.Preload("Hashtag").Where("user_id = ?", userID)
+	if cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	var follows []models.HashtagFollow
+	if err := query.Order("created_at DESC, id DESC").
+		Limit(limit + 1).
+		Find(&follows).Error; err != nil {
+		return nil, err
+	}
+
+	hasMore := len(follows) > limit
+	if hasMore {
+		follows = follows[:limit]
+	}
+
+	responses := make([]models.HashtagResponse, len(follows))
+	for i, follow := range follows {
+		responses[i] = follow.Hashtag.ToResponse()
+		responses[i].IsFollowing = true
+	}
+
+	page := &pagination.Page{Items: responses, HasMore: hasMore}
+	if hasMore {
+		last := follows[len(follows)-1]
+		page.NextCursor = pagination.NewCursor(last.CreatedAt, last.ID).Encode()
+	}
+	return page, nil
+}
+
+// GetHashtagUsage returns the hourly usage of a hashtag over a trending window,
+// oldest first, with empty hours included as zero
+func (s *HashtagService) GetHashtagUsage(name, window string) ([]models.HashtagUsagePoint, error) {
+	start, end, err := trendingBounds(window, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	hashtag, err := s.findHashtag(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var usages []models.HashtagUsage
+	if err := s.db.Where("hashtag_id = ? AND bucket_start >= ?", hashtag.ID, start).
+		Find(&usages).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int64]int, len(usages))
+	for _, usage := range usages {
+		counts[usage.BucketStart.Unix()] = usage.Count
+	}
+
+	var points []models.HashtagUsagePoint
+	for bucket := start; bucket.Before(end); bucket = bucket.Add(time.Hour) {
+		points = append(points, models.HashtagUsagePoint{BucketStart: bucket, Count: counts[bucket.Unix()]})
+	}
+	return points, nil
+}
+
+// GetHashtagPosts lists the posts tagged with a hashtag that the viewer can see, newest first
+func (s *HashtagService) GetHashtagPosts(viewerID uuid.UUID, name string, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	hashtag, err := s.findHashtag(name)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.db.Model(&models.Post{}).
+		Joins("JOIN post_hashtags ON post_hashtags.post_id = posts.id").
+		Where("post_hashtags.hashtag_id = ?", hashtag.ID).
+		Scopes(visiblePostsScope(viewerID))
+	if cursor != nil {
+		query = query.Where("(posts.created_at, posts.id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	var posts []models.Post
+	if err := query.Preload("User").
+		Order("posts.created_at DESC, posts.id DESC").
+		Limit(limit + 1).
+		Find(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	return s.postService.buildPostPage(viewerID, posts, limit)
+}
+
+func (s *HashtagService) findHashtag(name string) (*models.Hashtag, error) {
+	normalized, ok := utils.NormalizeHashtag(name)
+	if !ok {
+		return nil, apperrors.ErrHashtagNotFound
+	}
+
+	var hashtag models.Hashtag
+	if err := s.db.Where("name = ?", normalized).First(&hashtag).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrHashtagNotFound
+		}
+		return nil, err
+	}
+	return &hashtag, nil
+}
+
+// syncPostHashtags links a post to the hashtags of its caption, dropping links that are
+// no longer present, and keeps PostCount in step. Hashtags are always written in the same
+// order so posts sharing tags in a different order cannot deadlock.
+func syncPostHashtags(tx *gorm.DB, postID uuid.UUID, caption string) error {
+	names := utils.ExtractHashtags(caption)
+	slices.Sort(names)
+
+	var current []uuid.UUID
+	if err := tx.Model(&postHashtag{}).Where("post_id = ?", postID).Pluck("hashtag_id", &current).Error; err != nil {
+		return err
+	}
+
+	wanted, err := upsertHashtags(tx, names)
+	if err != nil {
+		return err
+	}
+
+	added := difference(wanted, current)
+	removed := difference(current, wanted)
+	slices.SortFunc(added, compareIDs)
+	slices.SortFunc(removed, compareIDs)
+	if err := lockHashtags(tx, append(slices.Clone(added), removed...)); err != nil {
+		return err
+	}
+
+	if len(added) > 0 {
+		links := make([]postHashtag, len(added))
+		for i, hashtagID := range added {
+			links[i] = postHashtag{PostID: postID, HashtagID: hashtagID}
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Hashtag{}).
+			Where("id IN ?", added).
+			UpdateColumn("post_count", gorm.Expr("post_count + 1")).Error; err != nil {
+			return err
+		}
+		if err := recordHashtagUsage(tx, added); err != nil {
+			return err
+		}
+	}
+
+	if len(removed) > 0 {
+		if err := tx.Where("post_id = ? AND hashtag_id IN ?", postID, removed).Delete(&postHashtag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Hashtag{}).
+			Where("id IN ?", removed).
+			UpdateColumn("post_count", gorm.Expr("GREATEST(post_count - 1, 0)")).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upsertHashtags creates any missing hashtags by normalized name and returns the IDs of all of them.
+// ON CONFLICT keeps concurrent writers introducing the same tag from hitting a unique violation.
+func upsertHashtags(tx *gorm.DB, names []string) ([]uuid.UUID, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	hashtags := make([]models.Hashtag, len(names))
+	for i, name := range names {
+		hashtags[i] = models.Hashtag{Name: name}
+	}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoNothing: true,
+	}).Create(&hashtags).Error; err != nil {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	if err := tx.Model(&models.Hashtag{}).Where("name IN ?", names).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// recordHashtagUsage counts one use of each hashtag in the current hourly bucket.
+// Usage is never decremented: trending measures tagging activity, not live posts.
+func recordHashtagUsage(tx *gorm.DB, hashtagIDs []uuid.UUID) error {
+	bucket := time.Now().UTC().Truncate(time.Hour)
+	usages := make([]models.HashtagUsage, len(hashtagIDs))
+	for i, hashtagID := range hashtagIDs {
+		usages[i] = models.HashtagUsage{HashtagID: hashtagID, BucketStart: bucket, Count: 1}
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "hashtag_id"}, {Name: "bucket_start"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("hashtag_usages.count + 1")}),
+	}).Create(&usages).Error
+}
+
+// lockHashtags locks the hashtags whose counters are about to change, in ID order
+func lockHashtags(tx *gorm.DB, hashtagIDs []uuid.UUID) error {
+	if len(hashtagIDs) == 0 {
+		return nil
+	}
+	var locked []uuid.UUID
+	return tx.Model(&models.Hashtag{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", hashtagIDs).
+		Order("id").
+		Pluck("id", &locked).Error
+}
+
+// compareIDs orders UUIDs the way Postgres does
+func compareIDs(a, b uuid.UUID) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+// difference returns the IDs of a that are not in b
+func difference(a, b []uuid.UUID) []uuid.UUID {
+	exclude := make(map[uuid.UUID]bool, len(b))
+	for _, id := range b {
+		exclude[id] = true
+	}
+
+	var result []uuid.UUID
+	for _, id := range a {
+		if !exclude[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
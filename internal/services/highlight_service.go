@@ -0,0 +1,473 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/storage"
+	"social-media-backend/pkg/constants"
+	apperrors "social-media-backend/pkg/errors"
+)
+
+type HighlightService struct {
+	db           *gorm.DB
+	storage      storage.Storage
+	storyService *StoryService
+}
+
+func NewHighlightService(db *gorm.DB, storage storage.Storage, storyService *StoryService) *HighlightService {
+	return &HighlightService{db: db, storage: storage, storyService: storyService}
+}
+
+// CreateHighlight creates a highlight below the user's existing ones from live or expired stories
+func (s *HighlightService) CreateHighlight(userID uuid.UUID, req *models.CreateHighlightRequest) (*models.HighlightResponse, error) {
+	highlight := models.Highlight{UserID: userID, Title: req.Title}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, userID); err != nil {
+			return err
+		}
+
+		var maxPosition int
+		if err := tx.Model(&models.Highlight{}).
+			Where("user_id = ?", userID).
+			Select("COALESCE(MAX(position), 0)").
+			Scan(&maxPosition).Error; err != nil {
+			return err
+		}
+		highlight.Position = maxPosition + 1
+
+		if err := tx.Create(&highlight).Error; err != nil {
+			return err
+		}
+		return addHighlightStories(tx, &highlight, req.StoryIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetHighlight(userID, highlight.ID)
+}
+
+// GetHighlight returns a highlight with its stories in order
+func (s *HighlightService) GetHighlight(viewerID, highlightID uuid.UUID) (*models.HighlightResponse, error) {
+	var highlight models.Highlight
+	if err := s.db.Preload("User").First(&highlight, "id = ?", highlightID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrHighlightNotFound
+		}
+		return nil, err
+	}
+
+	allowed, err := s.storyService.canViewStoriesOf(viewerID, &highlight.User)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, apperrors.ErrHighlightNotFound
+	}
+
+	// Highlighted stories have usually expired and been swept
+	var items []models.HighlightItem
+	if err := s.db.Preload("Story", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).
+		Preload("Story.User").
+		Where("highlight_id = ?", highlight.ID).
+		Order("position ASC, created_at ASC").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	stories := make([]models.Story, len(items))
+	for i, item := range items {
+		stories[i] = item.Story
+	}
+	storyResponses, err := s.storyService.buildStoryResponses(viewerID, stories)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := s.buildHighlightResponses([]models.Highlight{highlight})
+	if err != nil {
+		return nil, err
+	}
+	responses[0].Stories = storyResponses
+	return &responses[0], nil
+}
+
+// GetUserHighlights lists the highlights on a user's profile in order
+func (s *HighlightService) GetUserHighlights(viewerID, userID uuid.UUID) ([]models.HighlightResponse, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	allowed, err := s.storyService.canViewStoriesOf(viewerID, &user)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, apperrors.ErrPrivateAccount
+	}
+
+	var highlights []models.Highlight
+	if err := s.db.Where("user_id = ?", userID).
+		Order("position ASC, created_at ASC").
+		Find(&highlights).Error; err != nil {
+		return nil, err
+	}
+	return s.buildHighlightResponses(highlights)
+}
+
+// UpdateHighlight renames a highlight or sets one of its stories as the cover, which
+// replaces an uploaded cover image
+func (s *HighlightService) UpdateHighlight(ctx context.Context, userID, highlightID uuid.UUID, req *models.UpdateHighlightRequest) (*models.HighlightResponse, error) {
+	var oldCoverKey string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		highlight, err := findOwnedHighlight(tx, userID, highlightID)
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{}
+		if req.Title != nil {
+			updates["title"] = *req.Title
+		}
+		if req.CoverStoryID != nil {
+			var count int64
+			if err := tx.Model(&models.HighlightItem{}).
+				Where("highlight_id = ? AND story_id = ?", highlight.ID, *req.CoverStoryID).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return apperrors.ErrStoryNotInHighlight
+			}
+			updates["cover_story_id"] = *req.CoverStoryID
+			updates["cover_url"] = ""
+			updates["cover_key"] = ""
+			oldCoverKey = highlight.CoverKey
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Model(highlight).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.deleteCover(ctx, oldCoverKey)
+	return s.GetHighlight(userID, highlightID)
+}
+
+// SetCover uploads a cover image for a highlight, replacing the previous cover
+func (s *HighlightService) SetCover(ctx context.Context, userID, highlightID uuid.UUID, file *multipart.FileHeader) (*models.HighlightResponse, error) {
+	highlight, err := findOwnedHighlight(s.db, userID, highlightID)
+	if err != nil {
+		return nil, err
+	}
+
+	ext, err := validateMediaUpload(file, constants.PostTypeImage)
+	if err != nil {
+		return nil, err
+	}
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	key := fmt.Sprintf("highlights/%s/%s%s", userID, uuid.New(), ext)
+	url, err := s.storage.Save(ctx, key, src)
+	if err != nil {
+		log.Printf("highlight cover upload: %v", err)
+		return nil, apperrors.ErrFileUploadFailed
+	}
+
+	if err := s.db.Model(highlight).Updates(map[string]interface{}{
+		"cover_url":      url,
+		"cover_key":      key,
+		"cover_story_id": nil,
+	}).Error; err != nil {
+		s.deleteCover(ctx, key)
+		return nil, err
+	}
+
+	s.deleteCover(ctx, highlight.CoverKey)
+	return s.GetHighlight(userID, highlightID)
+}
+
+// DeleteHighlight removes a highlight. Its stories are kept, but their media becomes
+// eligible for removal by the story sweeper unless they are in another highlight.
+func (s *HighlightService) DeleteHighlight(ctx context.Context, userID, highlightID uuid.UUID) error {
+	var coverKey string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		highlight, err := findOwnedHighlight(tx, userID, highlightID)
+		if err != nil {
+			return err
+		}
+		coverKey = highlight.CoverKey
+
+		if err := tx.Where("highlight_id = ?", highlight.ID).Delete(&models.HighlightItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(highlight).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	s.deleteCover(ctx, coverKey)
+	return nil
+}
+
+// AddStories appends stories to a highlight. Stories already in it are skipped.
+func (s *HighlightService) AddStories(userID, highlightID uuid.UUID, storyIDs []uuid.UUID) (*models.HighlightResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, userID); err != nil {
+			return err
+		}
+		highlight, err := findOwnedHighlight(tx, userID, highlightID)
+		if err != nil {
+			return err
+		}
+		return addHighlightStories(tx, highlight, storyIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetHighlight(userID, highlightID)
+}
+
+// RemoveStory takes a story out of a highlight
+func (s *HighlightService) RemoveStory(userID, highlightID, storyID uuid.UUID) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		highlight, err := findOwnedHighlight(tx, userID, highlightID)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Where("highlight_id = ? AND story_id = ?", highlight.ID, storyID).Delete(&models.HighlightItem{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperrors.ErrStoryNotInHighlight
+		}
+
+		if highlight.CoverStoryID != nil && *highlight.CoverStoryID == storyID {
+			return tx.Model(highlight).Update("cover_story_id", nil).Error
+		}
+		return nil
+	})
+}
+
+// ReorderStories sets the order of the stories in a highlight. storyIDs must list every
+// story of the highlight exactly once.
+func (s *HighlightService) ReorderStories(userID, highlightID uuid.UUID, storyIDs []uuid.UUID) (*models.HighlightResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, userID); err != nil {
+			return err
+		}
+		highlight, err := findOwnedHighlight(tx, userID, highlightID)
+		if err != nil {
+			return err
+		}
+
+		var existing []uuid.UUID
+		if err := tx.Model(&models.HighlightItem{}).Where("highlight_id = ?", highlight.ID).Pluck("story_id", &existing).Error; err != nil {
+			return err
+		}
+		if len(existing) != len(storyIDs) {
+			return apperrors.ErrInvalidInput
+		}
+
+		remaining := make(map[uuid.UUID]bool, len(existing))
+		for _, id := range existing {
+			remaining[id] = true
+		}
+		for _, id := range storyIDs {
+			if !remaining[id] {
+				return apperrors.ErrStoryNotInHighlight
+			}
+			delete(remaining, id)
+		}
+
+		for i, id := range storyIDs {
+			if err := tx.Model(&models.HighlightItem{}).
+				Where("highlight_id = ? AND story_id = ?", highlight.ID, id).
+				Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetHighlight(userID, highlightID)
+}
+
+// buildHighlightResponses adds story counts and resolves the cover: the uploaded image,
+// else the chosen cover story, else the first story of the highlight
+func (s *HighlightService) buildHighlightResponses(highlights []models.Highlight) ([]models.HighlightResponse, error) {
+	responses := make([]models.HighlightResponse, 0, len(highlights))
+	if len(highlights) == 0 {
+		return responses, nil
+	}
+
+	ids := make([]uuid.UUID, len(highlights))
+	for i, highlight := range highlights {
+		ids[i] = highlight.ID
+	}
+
+	type highlightStats struct {
+		HighlightID   uuid.UUID
+		StoriesCount  int
+		CoverStoryURL string
+		FirstStoryURL string
+	}
+	var stats []highlightStats
+	if err := s.db.Raw(`
+		SELECT hi.highlight_id,
+			COUNT(*) AS stories_count,
+			COALESCE((ARRAY_AGG(st.media_url) FILTER (WHERE st.id = h.cover_story_id))[1], '') AS cover_story_url,
+			(ARRAY_AGG(st.media_url ORDER BY hi.position, hi.created_at))[1] AS first_story_url
+		FROM highlight_items hi
+		JOIN highlights h ON h.id = hi.highlight_id
+		JOIN stories st ON st.id = hi.story_id
+		WHERE hi.highlight_id IN ?
+		GROUP BY hi.highlight_id`, ids).Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]highlightStats, len(stats))
+	for _, stat := range stats {
+		byID[stat.HighlightID] = stat
+	}
+
+	for _, highlight := range highlights {
+		stat := byID[highlight.ID]
+		cover := highlight.CoverURL
+		if cover == "" {
+			cover = stat.CoverStoryURL
+		}
+		if cover == "" {
+			cover = stat.FirstStoryURL
+		}
+		responses = append(responses, models.HighlightResponse{
+			ID:           highlight.ID,
+			Title:        highlight.Title,
+			CoverURL:     cover,
+			Position:     highlight.Position,
+			StoriesCount: stat.StoriesCount,
+			CreatedAt:    highlight.CreatedAt,
+			UpdatedAt:    highlight.UpdatedAt,
+		})
+	}
+	return responses, nil
+}
+
+func (s *HighlightService) deleteCover(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	if err := s.storage.Delete(ctx, key); err != nil {
+		log.Printf("highlight cover cleanup: %v", err)
+	}
+}
+
+func findOwnedHighlight(db *gorm.DB, userID, highlightID uuid.UUID) (*models.Highlight, error) {
+	var highlight models.Highlight
+	if err := db.First(&highlight, "id = ?", highlightID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrHighlightNotFound
+		}
+		return nil, err
+	}
+	if highlight.UserID != userID {
+		return nil, apperrors.ErrUnauthorizedAction
+	}
+	return &highlight, nil
+}
+
+// addHighlightStories appends the user's stories to a highlight in the given order. Expired
+// stories can be added as long as their media has not been removed yet; the stories stay
+// locked until the items are committed, so the sweeper cannot remove the media meanwhile.
+func addHighlightStories(tx *gorm.DB, highlight *models.Highlight, storyIDs []uuid.UUID) error {
+	var stories []models.Story
+	if err := tx.Unscoped().
+		Clauses(clause.Locking{Strength: "SHARE"}).
+		Select("id", "media_key").
+		Where("id IN ? AND user_id = ?", storyIDs, highlight.UserID).
+		Find(&stories).Error; err != nil {
+		return err
+	}
+
+	found := make(map[uuid.UUID]bool, len(stories))
+	for _, story := range stories {
+		if story.MediaKey == "" {
+			return apperrors.ErrStoryMediaUnavailable
+		}
+		found[story.ID] = true
+	}
+
+	var existing []uuid.UUID
+	if err := tx.Model(&models.HighlightItem{}).Where("highlight_id = ?", highlight.ID).Pluck("story_id", &existing).Error; err != nil {
+		return err
+	}
+	present := make(map[uuid.UUID]bool, len(existing))
+	for _, id := range existing {
+		present[id] = true
+	}
+
+	var maxPosition int
+	if err := tx.Model(&models.HighlightItem{}).
+		Where("highlight_id = ?", highlight.ID).
+		Select("COALESCE(MAX(position), 0)").
+		Scan(&maxPosition).Error; err != nil {
+		return err
+	}
+
+	var items []models.HighlightItem
+	for _, id := range storyIDs {
+		if !found[id] {
+			return apperrors.ErrStoryNotFound
+		}
+		if present[id] {
+			continue
+		}
+		present[id] = true
+		items = append(items, models.HighlightItem{
+			HighlightID: highlight.ID,
+			StoryID:     id,
+			Position:    maxPosition + len(items) + 1,
+		})
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	if len(existing)+len(items) > constants.MaxHighlightStories {
+		return apperrors.ErrHighlightFull
+	}
+	if err := tx.Create(&items).Error; err != nil {
+		return err
+	}
+	return tx.Model(highlight).Update("updated_at", time.Now()).Error
+}
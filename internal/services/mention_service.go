@@ -0,0 +1,213 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/utils"
+	"social-media-backend/pkg/constants"
+	"social-media-backend/pkg/pagination"
+)
+
+type MentionService struct {
+	db          *gorm.DB
+	postService *PostService
+}
+
+func NewMentionService(db *gorm.DB, postService *PostService) *MentionService {
+	return &MentionService{db: db, postService: postService}
+}
+
+// mentionSource identifies the text mentions belong to. Exactly one field is set.
+type mentionSource struct {
+	PostID    *uuid.UUID
+	CommentID *uuid.UUID
+	StoryID   *uuid.UUID
+}
+
+func (m mentionSource) scope(db *gorm.DB) *gorm.DB {
+	switch {
+	case m.PostID != nil:
+		return db.Where("post_id = ?", *m.PostID)
+	case m.CommentID != nil:
+		return db.Where("comment_id = ?", *m.CommentID)
+	default:
+		return db.Where("story_id = ?", *m.StoryID)
+	}
+}
+
+// GetMentionedPosts lists the posts whose caption mentions the user, newest first,
+// limited to the posts the viewer can see
+func (s *MentionService) GetMentionedPosts(viewerID, userID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	query := s.db.Model(&models.Post{}).
+		Where("posts.id IN (SELECT post_id FROM mentions WHERE user_id = ? AND post_id IS NOT NULL)", userID).
+		Scopes(visiblePostsScope(viewerID))
+	if cursor != nil {
+		query = query.Where("(posts.created_at, posts.id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	var posts []models.Post
+	if err := query.Preload("User").
+		Order("posts.created_at DESC, posts.id DESC").
+		Limit(limit + 1).
+		Find(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	return s.postService.buildPostPage(viewerID, posts, limit)
+}
+
+// syncMentions replaces the mentions of source with the @mentions of text that resolve
+// to users accepting mentions from the actor. It returns the users who were not
+// mentioned there before, so editing a text does not notify the same people twice.
+func syncMentions(tx *gorm.DB, source mentionSource, actorID uuid.UUID, text string) ([]uuid.UUID, error) {
+	var previous []uuid.UUID
+	if err := tx.Model(&models.Mention{}).Scopes(source.scope).Distinct().Pluck("user_id", &previous).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Scopes(source.scope).Delete(&models.Mention{}).Error; err != nil {
+		return nil, err
+	}
+
+	matches := utils.ExtractMentions(text)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	users, err := resolveMentions(tx, actorID, matches)
+	if err != nil {
+		return nil, err
+	}
+
+	var mentions []models.Mention
+	for _, match := range matches {
+		user, ok := users[strings.ToLower(match.Username)]
+		if !ok {
+			continue
+		}
+		mentions = append(mentions, models.Mention{
+			UserID:    user.ID,
+			ActorID:   actorID,
+			PostID:    source.PostID,
+			CommentID: source.CommentID,
+			StoryID:   source.StoryID,
+			Offset:    match.Offset,
+			Length:    match.Length,
+		})
+	}
+	if len(mentions) == 0 {
+		return nil, nil
+	}
+	if err := tx.Create(&mentions).Error; err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool, len(previous))
+	for _, id := range previous {
+		seen[id] = true
+	}
+	var added []uuid.UUID
+	for _, mention := range mentions {
+		if !seen[mention.UserID] {
+			seen[mention.UserID] = true
+			added = append(added, mention.UserID)
+		}
+	}
+	return added, nil
+}
+
+// resolveMentions maps the lowercased usernames of matches to the users who may be
+// mentioned by the actor. Usernames match case-insensitively, preferring an exact match.
+// Users who blocked or are blocked by the actor, and users whose mention policy excludes
+// the actor, are left out.
+func resolveMentions(tx *gorm.DB, actorID uuid.UUID, matches []utils.MentionMatch) (map[string]models.User, error) {
+	exact := make(map[string]bool, len(matches))
+	names := make([]string, len(matches))
+	for i, match := range matches {
+		exact[match.Username] = true
+		names[i] = strings.ToLower(match.Username)
+	}
+
+	var users []models.User
+	if err := tx.Select("id", "username", "mention_policy").
+		Where("LOWER(username) IN ?", names).
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+	blocked, err := blockedAmong(tx, actorID, ids)
+	if err != nil {
+		return nil, err
+	}
+	var followerIDs []uuid.UUID
+	if err := tx.Model(&models.Follow{}).
+		Where("following_id = ? AND follower_id IN ? AND status = ?", actorID, ids, constants.FollowStatusAccepted).
+		Pluck("follower_id", &followerIDs).Error; err != nil {
+		return nil, err
+	}
+	followsActor := make(map[uuid.UUID]bool, len(followerIDs))
+	for _, id := range followerIDs {
+		followsActor[id] = true
+	}
+
+	resolved := make(map[string]models.User, len(users))
+	for _, user := range users {
+		if user.ID != actorID {
+			if blocked[user.ID] {
+				continue
+			}
+			switch user.MentionPolicy {
+			case constants.MentionPolicyNobody:
+				continue
+			case constants.MentionPolicyFollowing:
+				if !followsActor[user.ID] {
+					continue
+				}
+			}
+		}
+
+		key := strings.ToLower(user.Username)
+		if current, ok := resolved[key]; ok && exact[current.Username] {
+			continue
+		}
+		resolved[key] = user
+	}
+	return resolved, nil
+}
+
+// loadMentions returns the mentions of the given posts, comments or stories keyed by
+// their ID, in order of appearance. column is post_id, comment_id or story_id.
+func loadMentions(db *gorm.DB, column string, ids []uuid.UUID) (map[uuid.UUID][]models.MentionResponse, error) {
+	var rows []struct {
+		SourceID   uuid.UUID
+		UserID     uuid.UUID
+		Username   string
+		CharOffset int
+		CharLength int
+	}
+	if err := db.Table("mentions").
+		Select("mentions."+column+" AS source_id, mentions.user_id, users.username, mentions.char_offset, mentions.char_length").
+		Joins("JOIN users ON users.id = mentions.user_id AND users.deleted_at IS NULL").
+		Where("mentions."+column+" IN ?", ids).
+		Order("mentions.char_offset").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	mentions := make(map[uuid.UUID][]models.MentionResponse)
+	for _, row := range rows {
+		mentions[row.SourceID] = append(mentions[row.SourceID], models.MentionResponse{
+			UserID:   row.UserID,
+			Username: row.Username,
+			Offset:   row.CharOffset,
+			Length:   row.CharLength,
+		})
+	}
+	return mentions, nil
+}
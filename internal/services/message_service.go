@@ -0,0 +1,448 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/storage"
+	"social-media-backend/pkg/constants"
+	apperrors "social-media-backend/pkg/errors"
+	"social-media-backend/pkg/pagination"
+)
+
+type MessageService struct {
+	db      *gorm.DB
+	storage storage.Storage
+	hub     *RealtimeHub
+}
+
+func NewMessageService(db *gorm.DB, storage storage.Storage, hub *RealtimeHub) *MessageService {
+	return &MessageService{db: db, storage: storage, hub: hub}
+}
+
+// SendDirectMessage sends a message to a user, starting the direct conversation with them
+// if needed. Users who blocked each other cannot message, and messages from users the
+// receiver does not follow land in their message requests.
+func (s *MessageService) SendDirectMessage(ctx context.Context, senderID uuid.UUID, req *models.SendMessageRequest, file *multipart.FileHeader) (*models.MessageResponse, error) {
+	if req.ReceiverID == senderID {
+		return nil, apperrors.ErrCannotMessageSelf
+	}
+	conversation, err := s.openDirectConversation(senderID, req.ReceiverID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.SendMessage(ctx, senderID, conversation.ID, &models.ConversationMessageRequest{
+		Content:   req.Content,
+		MediaType: req.MediaType,
+	}, file)
+}
+
+// SendMessage sends a message with optional media to a conversation the sender is part of.
+// Sending marks everything before the message as read for the sender, and replying to a
+// message request accepts it.
+func (s *MessageService) SendMessage(ctx context.Context, senderID, conversationID uuid.UUID, req *models.ConversationMessageRequest, file *multipart.FileHeader) (*models.MessageResponse, error) {
+	content := strings.TrimSpace(req.Content)
+	if content == "" && file == nil {
+		return nil, apperrors.ErrEmptyMessage
+	}
+	participant, err := s.checkCanSend(senderID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := models.Message{
+		ConversationID: conversationID,
+		SenderID:       senderID,
+		Kind:           constants.MessageKindText,
+		Content:        content,
+	}
+
+	if file != nil {
+		if req.MediaType == "" {
+			return nil, apperrors.ErrInvalidFileType
+		}
+		ext, err := validateMediaUpload(file, req.MediaType)
+		if err != nil {
+			return nil, err
+		}
+		src, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer src.Close()
+
+		key := fmt.Sprintf("messages/%s/%s%s", senderID, uuid.New(), ext)
+		url, err := s.storage.Save(ctx, key, src)
+		if err != nil {
+			log.Printf("message upload: %v", err)
+			return nil, apperrors.ErrFileUploadFailed
+		}
+		message.MediaURL = url
+		message.MediaKey = key
+		message.MediaType = req.MediaType
+	}
+
+	if err := s.createMessage(participant, &message); err != nil {
+		if message.MediaKey != "" {
+			if err := s.storage.Delete(ctx, message.MediaKey); err != nil {
+				log.Printf("message upload cleanup: %v", err)
+			}
+		}
+		return nil, err
+	}
+
+	response, err := s.getMessage(senderID, message.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.hub.publishMessage(response)
+	return response, nil
+}
+
+// SendStoryMessage sends the author of a story a reply or reaction referring to it, under
+// the same rules as SendDirectMessage. The story's own reply policy is checked by the caller.
+func (s *MessageService) SendStoryMessage(senderID uuid.UUID, story *models.Story, kind, content string) (*models.MessageResponse, error) {
+	conversation, err := s.openDirectConversation(senderID, story.UserID)
+	if err != nil {
+		return nil, err
+	}
+	participant, err := s.checkCanSend(senderID, conversation.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := models.Message{
+		ConversationID: conversation.ID,
+		SenderID:       senderID,
+		Kind:           kind,
+		Content:        content,
+		StoryID:        &story.ID,
+	}
+	if err := s.createMessage(participant, &message); err != nil {
+		return nil, err
+	}
+
+	response, err := s.getMessage(senderID, message.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.hub.publishMessage(response)
+	return response, nil
+}
+
+// GetMessages returns the messages of a conversation the user can see, most recent first.
+// Messages from before the user joined and those they deleted for themselves are left out.
+func (s *MessageService) GetMessages(userID, conversationID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	participant, err := findParticipant(s.db, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.db.Preload("Sender").
+		Scopes(visibleMessagesScope(participant)).
+		Where("conversation_id = ?", conversationID)
+	if cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	var messages []models.Message
+	if err := query.Order("created_at DESC, id DESC").
+		Limit(limit + 1).
+		Find(&messages).Error; err != nil {
+		return nil, err
+	}
+
+	hasMore := len(messages) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+
+	responses, err := buildMessageResponses(s.db, userID, messages)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &pagination.Page{Items: responses, HasMore: hasMore}
+	if hasMore {
+		last := messages[len(messages)-1]
+		page.NextCursor = pagination.NewCursor(last.CreatedAt, last.ID).Encode()
+	}
+	return page, nil
+}
+
+// DeleteMessage hides a message from the user's view of the conversation only
+func (s *MessageService) DeleteMessage(userID, messageID uuid.UUID) error {
+	message, err := findVisibleMessage(s.db, userID, messageID)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.HiddenMessage{MessageID: message.ID, UserID: userID}).Error
+}
+
+// UnsendMessage removes a message the user sent for every participant, along with its
+// reactions and media. System messages cannot be unsent.
+func (s *MessageService) UnsendMessage(ctx context.Context, userID, messageID uuid.UUID) error {
+	message, err := findVisibleMessage(s.db, userID, messageID)
+	if err != nil {
+		return err
+	}
+	if message.SenderID != userID || message.Kind == constants.MessageKindSystem {
+		return apperrors.ErrUnauthorizedAction
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("message_id = ?", message.ID).Delete(&models.Like{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("message_id = ?", message.ID).Delete(&models.HiddenMessage{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(message).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	if message.MediaKey != "" {
+		if err := s.storage.Delete(ctx, message.MediaKey); err != nil {
+			log.Printf("message media cleanup: %v", err)
+		}
+	}
+
+	s.hub.PublishToConversation(message.ConversationID, uuid.Nil, models.RealtimeEvent{
+		Type:           constants.RealtimeEventMessageUnsent,
+		ConversationID: &message.ConversationID,
+		Data:           models.UnsentMessage{MessageID: message.ID},
+	})
+	return nil
+}
+
+// MessagesSince returns the messages the user can see across their conversations that were
+// sent after lastMessageID, oldest first, for clients resuming a real-time connection. It
+// reports whether the result is complete or the client has to refetch over HTTP.
+func (s *MessageService) MessagesSince(userID, lastMessageID uuid.UUID, limit int) ([]models.MessageResponse, bool, error) {
+	// The message may have been unsent since the client saw it, it still marks the position
+	var last models.Message
+	if err := s.db.Unscoped().Select("id", "conversation_id", "created_at").First(&last, "id = ?", lastMessageID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, apperrors.ErrMessageNotFound
+		}
+		return nil, false, err
+	}
+
+	var messages []models.Message
+	if err := s.db.Preload("Sender").
+		Select("messages.*").
+		Joins("JOIN conversation_participants p ON p.conversation_id = messages.conversation_id AND p.user_id = ?", userID).
+		Where("messages.created_at >= p.joined_at").
+		Where("NOT EXISTS (SELECT 1 FROM hidden_messages h WHERE h.message_id = messages.id AND h.user_id = ?)", userID).
+		Where("(messages.created_at, messages.id) > (?, ?)", last.CreatedAt, last.ID).
+		Order("messages.created_at ASC, messages.id ASC").
+		Limit(limit + 1).
+		Find(&messages).Error; err != nil {
+		return nil, false, err
+	}
+
+	complete := len(messages) <= limit
+	if !complete {
+		messages = messages[:limit]
+	}
+
+	responses, err := buildMessageResponses(s.db, userID, messages)
+	if err != nil {
+		return nil, false, err
+	}
+	return responses, complete, nil
+}
+
+// checkCanSend makes sure the sender is part of the conversation and, in a direct
+// conversation, that neither side blocked the other and a pending request is still allowed
+// by the receiver's message policy. It returns the sender's membership.
+// openDirectConversation returns the direct conversation of the sender with a user, starting
+// it if needed, unless one of them blocked the other
+func (s *MessageService) openDirectConversation(senderID, receiverID uuid.UUID) (*models.Conversation, error) {
+	if err := checkCanAddUsers(s.db, senderID, []uuid.UUID{receiverID}); err != nil {
+		return nil, err
+	}
+
+	var conversation *models.Conversation
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		conversation, err = findOrCreateDirectConversation(tx, senderID, receiverID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conversation, nil
+}
+
+// createMessage stores a message checked by checkCanSend and moves the sender's read cursor
+// to it. Sending from a message request accepts the request.
+func (s *MessageService) createMessage(participant *models.ConversationParticipant, message *models.Message) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if participant.Status == constants.ParticipantStatusPending {
+			if err := tx.Model(participant).Update("status", constants.ParticipantStatusAccepted).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(message).Error; err != nil {
+			return err
+		}
+		_, err := advanceReadCursor(tx, message.ConversationID, message.SenderID, message, time.Now())
+		return err
+	})
+}
+
+func (s *MessageService) checkCanSend(senderID, conversationID uuid.UUID) (*models.ConversationParticipant, error) {
+	participant, err := findParticipant(s.db, conversationID, senderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var conversation models.Conversation
+	if err := s.db.Select("id", "kind").First(&conversation, "id = ?", conversationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrConversationNotFound
+		}
+		return nil, err
+	}
+	if conversation.Kind != constants.ConversationKindDirect {
+		return participant, nil
+	}
+
+	var others []models.ConversationParticipant
+	if err := s.db.Select("user_id", "status").
+		Where("conversation_id = ? AND user_id <> ?", conversationID, senderID).
+		Find(&others).Error; err != nil {
+		return nil, err
+	}
+	otherIDs := make([]uuid.UUID, len(others))
+	var pendingIDs []uuid.UUID
+	for i, other := range others {
+		otherIDs[i] = other.UserID
+		if other.Status == constants.ParticipantStatusPending {
+			pendingIDs = append(pendingIDs, other.UserID)
+		}
+	}
+
+	blocked, err := blockedAmong(s.db, senderID, otherIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocked) > 0 {
+		return nil, apperrors.ErrCannotMessageUser
+	}
+	if len(pendingIDs) > 0 {
+		if _, err := participantStatuses(s.db, senderID, pendingIDs); err != nil {
+			return nil, err
+		}
+	}
+	return participant, nil
+}
+
+// buildMessageResponses renders messages with their reactions and the stories they refer to.
+// Sender must be preloaded.
+func buildMessageResponses(db *gorm.DB, viewerID uuid.UUID, messages []models.Message) ([]models.MessageResponse, error) {
+	responses := make([]models.MessageResponse, len(messages))
+	if len(messages) == 0 {
+		return responses, nil
+	}
+
+	ids := make([]uuid.UUID, len(messages))
+	var storyIDs []uuid.UUID
+	for i, message := range messages {
+		ids[i] = message.ID
+		if message.StoryID != nil {
+			storyIDs = append(storyIDs, *message.StoryID)
+		}
+	}
+
+	reactionCounts, viewerReactions, err := loadReactionSummaries(db, viewerID, ReactionTargetMessage, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stories are referenced after they expire or are deleted, so they are looked up loosely
+	stories := make(map[uuid.UUID]models.Story)
+	if len(storyIDs) > 0 {
+		var rows []models.Story
+		if err := db.Unscoped().Where("id IN ?", storyIDs).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, story := range rows {
+			stories[story.ID] = story
+		}
+	}
+
+	now := time.Now()
+	for i := range messages {
+		response := messages[i].ToResponse()
+		response.ReactionCounts = reactionCounts[messages[i].ID]
+		response.ViewerReaction = viewerReactions[messages[i].ID]
+		if messages[i].StoryID != nil {
+			ref := &models.MessageStoryResponse{ID: *messages[i].StoryID}
+			if story, ok := stories[ref.ID]; ok && !story.DeletedAt.Valid && story.ExpiresAt.After(now) {
+				ref.Available = true
+				ref.MediaURL = story.MediaURL
+				ref.MediaType = story.MediaType
+			}
+			response.Story = ref
+		}
+		responses[i] = response
+	}
+	return responses, nil
+}
+
+func (s *MessageService) getMessage(viewerID, messageID uuid.UUID) (*models.MessageResponse, error) {
+	var message models.Message
+	if err := s.db.Preload("Sender").First(&message, "id = ?", messageID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrMessageNotFound
+		}
+		return nil, err
+	}
+
+	responses, err := buildMessageResponses(s.db, viewerID, []models.Message{message})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// findVisibleMessage loads a message the user can see in one of their conversations. Other
+// messages are indistinguishable from missing ones.
+func findVisibleMessage(db *gorm.DB, userID, messageID uuid.UUID) (*models.Message, error) {
+	var message models.Message
+	if err := db.First(&message, "id = ?", messageID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrMessageNotFound
+		}
+		return nil, err
+	}
+
+	participant, err := findParticipant(db, message.ConversationID, userID)
+	if errors.Is(err, apperrors.ErrConversationNotFound) {
+		return nil, apperrors.ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if message.CreatedAt.Before(participant.JoinedAt) {
+		return nil, apperrors.ErrMessageNotFound
+	}
+	return &message, nil
+}
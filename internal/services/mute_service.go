@@ -0,0 +1,86 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"social-media-backend/internal/models"
+	apperrors "social-media-backend/pkg/errors"
+	"social-media-backend/pkg/pagination"
+)
+
+type MuteService struct {
+	db *gorm.DB
+}
+
+func NewMuteService(db *gorm.DB) *MuteService {
+	return &MuteService{db: db}
+}
+
+// MuteUser hides another user's posts from the muter's feeds and explore page. Unlike a
+// block it leaves follows untouched. Muting twice is a no-op.
+func (s *MuteService) MuteUser(muterID, mutedID uuid.UUID) error {
+	if muterID == mutedID {
+		return apperrors.ErrCannotMuteSelf
+	}
+	if err := s.db.Select("id").First(&models.User{}, "id = ?", mutedID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrUserNotFound
+		}
+		return err
+	}
+
+	return s.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.Mute{MuterID: muterID, MutedID: mutedID}).Error
+}
+
+// UnmuteUser lifts a mute. Unmuting a user who is not muted is a no-op.
+func (s *MuteService) UnmuteUser(muterID, mutedID uuid.UUID) error {
+	return s.db.Where("muter_id = ? AND muted_id = ?", muterID, mutedID).
+		Delete(&models.Mute{}).Error
+}
+
+// GetMutedUsers lists the users the user has muted, most recent first
+func (s *MuteService) GetMutedUsers(userID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	query := s.db.Preload("Muted").Where("muter_id = ?", userID)
+	if cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	var mutes []models.Mute
+	if err := query.Order("created_at DESC, id DESC").
+		Limit(limit + 1).
+		Find(&mutes).Error; err != nil {
+		return nil, err
+	}
+
+	hasMore := len(mutes) > limit
+	if hasMore {
+		mutes = mutes[:limit]
+	}
+
+	users := make([]models.UserResponse, len(mutes))
+	for i, mute := range mutes {
+		users[i] = mute.Muted.ToResponse()
+	}
+
+	page := &pagination.Page{Items: users, HasMore: hasMore}
+	if hasMore {
+		last := mutes[len(mutes)-1]
+		page.NextCursor = pagination.NewCursor(last.CreatedAt, last.ID).Encode()
+	}
+	return page, nil
+}
+
+// notMutedScope hides posts of users the viewer muted
+func notMutedScope(viewerID uuid.UUID) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if viewerID == uuid.Nil {
+			return db
+		}
+		return db.Where("posts.user_id NOT IN (SELECT muted_id FROM mutes WHERE muter_id = ?)", viewerID)
+	}
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/pkg/pagination"
+)
+
+type NotificationService struct {
+	db *gorm.DB
+}
+
+func NewNotificationService(db *gorm.DB) *NotificationService {
+	return &NotificationService{db: db}
+}
+
+// Notify stores a notification unless users would be notified about their own actions.
+// Pass a transaction to create the notification atomically with the action that caused it.
+func (s *NotificationService) Notify(tx *gorm.DB, notification *models.Notification) error {
+	if notification.UserID == notification.ActorID {
+		return nil
+	}
+	if tx == nil {
+		tx = s.db
+	}
+	return tx.Create(notification).Error
+}
+
+// NotifyMany sends a copy of notification to each of userIDs
+func (s *NotificationService) NotifyMany(tx *gorm.DB, userIDs []uuid.UUID, notification models.Notification) error {
+	for _, userID := range userIDs {
+		n := notification
+		n.UserID = userID
+		if err := s.Notify(tx, &n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetNotifications lists the user's notifications, newest first
+func (s *NotificationService) GetNotifications(userID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	query := s.db.Preload("Actor").Where("user_id = ?", userID)
+	if cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	var notifications []models.Notification
+	if err := query.Order("created_at DESC, id DESC").
+		Limit(limit + 1).
+		Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+
+	hasMore := len(notifications) > limit
+	if hasMore {
+		notifications = notifications[:limit]
+	}
+
+	responses := make([]models.NotificationResponse, len(notifications))
+	for i := range notifications {
+		responses[i] = notifications[i].ToResponse()
+	}
+
+	page := &pagination.Page{Items: responses, HasMore: hasMore}
+	if hasMore {
+		last := notifications[len(notifications)-1]
+		page.NextCursor = pagination.NewCursor(last.CreatedAt, last.ID).Encode()
+	}
+	return page, nil
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/pkg/constants"
+	apperrors "social-media-backend/pkg/errors"
+)
+
+const pollCloserBatchSize = 100
+
+type PollService struct {
+	db          *gorm.DB
+	postService *PostService
+}
+
+func NewPollService(db *gorm.DB, postService *PostService) *PollService {
+	return &PollService{db: db, postService: postService}
+}
+
+// Vote casts the user's only ballot on the poll of a post
+func (s *PollService) Vote(userID, postID uuid.UUID, optionIDs []uuid.UUID) (*models.PostResponse, error) {
+	if _, err := s.postService.GetPost(userID, postID); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var poll models.Poll
+		if err := tx.Preload("Options").Where("post_id = ?", postID).First(&poll).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperrors.ErrPollNotFound
+			}
+			return err
+		}
+		if poll.IsClosed() {
+			return apperrors.ErrPollClosed
+		}
+
+		chosen, err := validatePollChoice(&poll, optionIDs)
+		if err != nil {
+			return err
+		}
+
+		ballot := models.PollBallot{PollID: poll.ID, UserID: userID}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&ballot)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperrors.ErrAlreadyVoted
+		}
+
+		votes := make([]models.PollVote, len(chosen))
+		for i, optionID := range chosen {
+			votes[i] = models.PollVote{BallotID: ballot.ID, OptionID: optionID}
+		}
+		if err := tx.Create(&votes).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.PollOption{}).
+			Where("id IN ?", chosen).
+			UpdateColumn("votes_count", gorm.Expr("votes_count + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Model(&poll).UpdateColumn("voters_count", gorm.Expr("voters_count + 1")).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.postService.GetPost(userID, postID)
+}
+
+// NotifyClosedPolls tells authors about polls that closed since the last run.
+// Rows are locked with SKIP LOCKED so several instances can run it concurrently.
+func (s *PollService) NotifyClosedPolls() (int, error) {
+	notified := 0
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var polls []models.Poll
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("closes_at <= ? AND notified_at IS NULL", time.Now()).
+			Order("closes_at ASC").
+			Limit(pollCloserBatchSize).
+			Find(&polls).Error; err != nil {
+			return err
+		}
+		if len(polls) == 0 {
+			return nil
+		}
+
+		postIDs := make([]uuid.UUID, len(polls))
+		pollIDs := make([]uuid.UUID, len(polls))
+		for i, poll := range polls {
+			postIDs[i] = poll.PostID
+			pollIDs[i] = poll.ID
+		}
+
+		// Deleted posts are skipped but their polls are still marked as handled
+		var posts []models.Post
+		if err := tx.Select("id", "user_id").Where("id IN ?", postIDs).Find(&posts).Error; err != nil {
+			return err
+		}
+
+		for _, post := range posts {
+			postID := post.ID
+			// System notification, so the author is also the actor
+			if err := tx.Create(&models.Notification{
+				UserID:  post.UserID,
+				ActorID: post.UserID,
+				Type:    constants.NotificationTypePollClosed,
+				PostID:  &postID,
+				Content: "Your poll has closed",
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		notified = len(posts)
+		return tx.Model(&models.Poll{}).Where("id IN ?", pollIDs).Update("notified_at", time.Now()).Error
+	})
+	return notified, err
+}
+
+// RunCloser periodically notifies authors of closed polls until ctx is cancelled
+func (s *PollService) RunCloser(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.NotifyClosedPolls(); err != nil {
+				log.Printf("poll closer: %v", err)
+			}
+		}
+	}
+}
+
+// createPoll attaches a poll to a post being created in tx
+func createPoll(tx *gorm.DB, postID uuid.UUID, req *models.CreatePollRequest) error {
+	duration := time.Until(req.ClosesAt)
+	if duration < constants.MinPollDuration || duration > constants.MaxPollDuration {
+		return apperrors.ErrInvalidPollDuration
+	}
+
+	poll := models.Poll{
+		PostID:         postID,
+		AllowsMultiple: req.AllowsMultiple,
+		ClosesAt:       req.ClosesAt,
+	}
+	for i, text := range req.Options {
+		poll.Options = append(poll.Options, models.PollOption{Text: text, Position: i + 1})
+	}
+
+	return tx.Create(&poll).Error
+}
+
+// validatePollChoice checks the options belong to the poll and returns them without duplicates
+func validatePollChoice(poll *models.Poll, optionIDs []uuid.UUID) ([]uuid.UUID, error) {
+	valid := make(map[uuid.UUID]bool, len(poll.Options))
+	for _, option := range poll.Options {
+		valid[option.ID] = true
+	}
+
+	seen := map[uuid.UUID]bool{}
+	chosen := make([]uuid.UUID, 0, len(optionIDs))
+	for _, id := range optionIDs {
+		if !valid[id] {
+			return nil, apperrors.ErrInvalidPollOption
+		}
+		if !seen[id] {
+			seen[id] = true
+			chosen = append(chosen, id)
+		}
+	}
+
+	if len(chosen) == 0 || (!poll.AllowsMultiple && len(chosen) > 1) {
+		return nil, apperrors.ErrInvalidPollOption
+	}
+	return chosen, nil
+}
+
+// loadPollResponses returns the polls of the given posts keyed by post ID, rendered for the viewer
+func loadPollResponses(db *gorm.DB, viewerID uuid.UUID, posts []models.Post) (map[uuid.UUID]*models.PollResponse, error) {
+	responses := map[uuid.UUID]*models.PollResponse{}
+	if len(posts) == 0 {
+		return responses, nil
+	}
+
+	authors := make(map[uuid.UUID]uuid.UUID, len(posts))
+	postIDs := make([]uuid.UUID, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.ID
+		authors[post.ID] = post.UserID
+	}
+
+	var polls []models.Poll
+	if err := db.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Where("post_id IN ?", postIDs).Find(&polls).Error; err != nil {
+		return nil, err
+	}
+	if len(polls) == 0 {
+		return responses, nil
+	}
+
+	viewerVotes := map[uuid.UUID][]uuid.UUID{}
+	if viewerID != uuid.Nil {
+		pollIDs := make([]uuid.UUID, len(polls))
+		for i, poll := range polls {
+			pollIDs[i] = poll.ID
+		}
+
+		var rows []struct {
+			PollID   uuid.UUID
+			OptionID uuid.UUID
+		}
+		if err := db.Table("poll_ballots").
+			Select("poll_ballots.poll_id, poll_votes.option_id").
+			Joins("JOIN poll_votes ON poll_votes.ballot_id = poll_ballots.id").
+			Where("poll_ballots.user_id = ? AND poll_ballots.poll_id IN ?", viewerID, pollIDs).
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			viewerVotes[row.PollID] = append(viewerVotes[row.PollID], row.OptionID)
+		}
+	}
+
+	for _, poll := range polls {
+		votes, hasVoted := viewerVotes[poll.ID]
+		closed := poll.IsClosed()
+		visible := closed || hasVoted || (viewerID != uuid.Nil && authors[poll.PostID] == viewerID)
+
+		response := &models.PollResponse{
+			ID:              poll.ID,
+			AllowsMultiple:  poll.AllowsMultiple,
+			ClosesAt:        poll.ClosesAt,
+			IsClosed:        closed,
+			HasVoted:        hasVoted,
+			ResultsVisible:  visible,
+			ViewerOptionIDs: votes,
+			Options:         make([]models.PollOptionResponse, 0, len(poll.Options)),
+		}
+		if visible {
+			votersCount := poll.VotersCount
+			response.VotersCount = &votersCount
+		}
+		for _, option := range poll.Options {
+			optionResponse := models.PollOptionResponse{ID: option.ID, Text: option.Text}
+			if visible {
+				votesCount := option.VotesCount
+				optionResponse.VotesCount = &votesCount
+			}
+			response.Options = append(response.Options, optionResponse)
+		}
+
+		responses[poll.PostID] = response
+	}
+	return responses, nil
+}
@@ -0,0 +1,714 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/pkg/constants"
+	apperrors "social-media-backend/pkg/errors"
+	"social-media-backend/pkg/pagination"
+)
+
+type PostService struct {
+	db                  *gorm.DB
+	notificationService *NotificationService
+	fanout              *FeedFanout
+}
+
+func NewPostService(db *gorm.DB, notificationService *NotificationService, fanout *FeedFanout) *PostService {
+	return &PostService{db: db, notificationService: notificationService, fanout: fanout}
+}
+
+// CreatePost creates a text post or the metadata of a media post
+func (s *PostService) CreatePost(userID uuid.UUID, req *models.CreatePostRequest) (*models.PostResponse, error) {
+	post := models.Post{
+		UserID:    userID,
+		Caption:   req.Caption,
+		MediaType: req.MediaType,
+		Location:  req.Location,
+		IsPublic:  true,
+	}
+	if post.MediaType == "" {
+		post.MediaType = constants.PostTypeText
+	}
+	if req.IsPublic != nil {
+		post.IsPublic = *req.IsPublic
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&post).Error; err != nil {
+			return err
+		}
+		if err := syncPostHashtags(tx, post.ID, post.Caption); err != nil {
+			return err
+		}
+		if err := s.syncPostMentions(tx, &post); err != nil {
+			return err
+		}
+		if req.Poll != nil {
+			return createPoll(tx, post.ID, req.Poll)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.fanout.Publish(&post)
+
+	return s.GetPost(userID, post.ID)
+}
+
+// GetPost returns a single post if the viewer is allowed to see it
+func (s *PostService) GetPost(viewerID, postID uuid.UUID) (*models.PostResponse, error) {
+	post, err := s.findPost(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := s.canViewPost(viewerID, post)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		// Hidden posts are indistinguishable from missing ones
+		return nil, apperrors.ErrPostNotFound
+	}
+
+	responses, err := s.buildPostResponses(viewerID, []models.Post{*post})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// UpdatePost updates a post owned by the user
+func (s *PostService) UpdatePost(userID, postID uuid.UUID, req *models.UpdatePostRequest) (*models.PostResponse, error) {
+	post, err := s.findOwnedPost(userID, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.ShareType == constants.ShareTypeRepost {
+		return nil, apperrors.ErrCannotEditRepost
+	}
+
+	updates := map[string]interface{}{}
+	if req.Caption != "" {
+		updates["caption"] = req.Caption
+	}
+	if req.Location != "" {
+		updates["location"] = req.Location
+	}
+	if req.IsPublic != nil {
+		updates["is_public"] = *req.IsPublic
+	}
+
+	if len(updates) > 0 {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(post).Updates(updates).Error; err != nil {
+				return err
+			}
+			if req.Caption == "" {
+				return nil
+			}
+			post.Caption = req.Caption
+			if err := syncPostHashtags(tx, post.ID, post.Caption); err != nil {
+				return err
+			}
+			return s.syncPostMentions(tx, post)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetPost(userID, postID)
+}
+
+// DeletePost soft deletes a post owned by the user
+func (s *PostService) DeletePost(userID, postID uuid.UUID) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, userID); err != nil {
+			return err
+		}
+
+		post, err := findOwnedPostTx(tx, userID, postID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(post).Error; err != nil {
+			return err
+		}
+		if err := syncPostHashtags(tx, post.ID, ""); err != nil {
+			return err
+		}
+
+		if post.PinPosition != nil {
+			if err := compactPinnedPosts(tx, userID); err != nil {
+				return err
+			}
+		}
+		if post.OriginalPostID != nil {
+			return adjustSharesCount(tx, *post.OriginalPostID, -1)
+		}
+		return nil
+	})
+}
+
+// GetUserPosts returns the profile grid of a user: pinned posts first on the first page,
+// then the remaining posts newest first. Archived posts never appear in the grid.
+func (s *PostService) GetUserPosts(viewerID, userID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	var author models.User
+	if err := s.db.First(&author, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	isFollower := false
+	if viewerID != uuid.Nil && viewerID != userID {
+		var err error
+		isFollower, err = s.isFollowing(viewerID, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	isOwner := viewerID == userID
+	if author.IsPrivate && !isOwner && !isFollower {
+		return nil, apperrors.ErrPrivateAccount
+	}
+
+	scope := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("user_id = ? AND is_archived = ?", userID, false)
+		if !isOwner && !isFollower {
+			db = db.Where("is_public = ?", true)
+		}
+		return db.Preload("User")
+	}
+
+	var pinned []models.Post
+	if cursor == nil {
+		if err := s.db.Scopes(scope).
+			Where("pin_position IS NOT NULL").
+			Order("pin_position ASC").
+			Find(&pinned).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	query := s.db.Scopes(scope).Where("pin_position IS NULL")
+	if cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	var recent []models.Post
+	if err := query.Order("created_at DESC, id DESC").
+		Limit(limit + 1).
+		Find(&recent).Error; err != nil {
+		return nil, err
+	}
+
+	hasMore := len(recent) > limit
+	if hasMore {
+		recent = recent[:limit]
+	}
+
+	responses, err := s.buildPostResponses(viewerID, append(pinned, recent...))
+	if err != nil {
+		return nil, err
+	}
+
+	page := &pagination.Page{Items: responses, HasMore: hasMore}
+	if hasMore {
+		last := recent[len(recent)-1]
+		page.NextCursor = pagination.NewCursor(last.CreatedAt, last.ID).Encode()
+	}
+	return page, nil
+}
+
+// ArchivePost hides a post from everyone but its owner. Likes, comments and counters are kept.
+func (s *PostService) ArchivePost(userID, postID uuid.UUID) (*models.PostResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, userID); err != nil {
+			return err
+		}
+
+		post, err := findOwnedPostTx(tx, userID, postID)
+		if err != nil {
+			return err
+		}
+		if post.IsArchived {
+			return apperrors.ErrPostArchived
+		}
+
+		now := time.Now()
+		if err := tx.Model(post).Updates(map[string]interface{}{
+			"is_archived":  true,
+			"archived_at":  now,
+			"pin_position": nil,
+		}).Error; err != nil {
+			return err
+		}
+
+		// An archived post cannot stay pinned on the grid
+		if post.PinPosition != nil {
+			return compactPinnedPosts(tx, userID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetPost(userID, postID)
+}
+
+// UnarchivePost restores an archived post to the profile grid
+func (s *PostService) UnarchivePost(userID, postID uuid.UUID) (*models.PostResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, userID); err != nil {
+			return err
+		}
+
+		post, err := findOwnedPostTx(tx, userID, postID)
+		if err != nil {
+			return err
+		}
+		if !post.IsArchived {
+			return apperrors.ErrPostNotArchived
+		}
+
+		return tx.Model(post).Updates(map[string]interface{}{
+			"is_archived": false,
+			"archived_at": nil,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetPost(userID, postID)
+}
+
+// GetArchivedPosts lists the user's own archived posts, most recently archived first.
+// The cursor position is the archive time rather than the creation time.
+func (s *PostService) GetArchivedPosts(userID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	query := s.db.Preload("User").Where("user_id = ? AND is_archived = ?", userID, true)
+	if cursor != nil {
+		query = query.Where("(archived_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	var posts []models.Post
+	if err := query.Order("archived_at DESC, id DESC").
+		Limit(limit + 1).
+		Find(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	hasMore := len(posts) > limit
+	if hasMore {
+		posts = posts[:limit]
+	}
+
+	responses, err := s.buildPostResponses(userID, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &pagination.Page{Items: responses, HasMore: hasMore}
+	if hasMore {
+		last := posts[len(posts)-1]
+		page.NextCursor = pagination.NewCursor(*last.ArchivedAt, last.ID).Encode()
+	}
+	return page, nil
+}
+
+// PinPost pins a post below the already pinned ones
+func (s *PostService) PinPost(userID, postID uuid.UUID) (*models.PostResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, userID); err != nil {
+			return err
+		}
+
+		post, err := findOwnedPostTx(tx, userID, postID)
+		if err != nil {
+			return err
+		}
+		if post.IsArchived {
+			return apperrors.ErrPostArchived
+		}
+		if post.PinPosition != nil {
+			return apperrors.ErrPostAlreadyPinned
+		}
+
+		var pinned int64
+		if err := tx.Model(&models.Post{}).
+			Where("user_id = ? AND pin_position IS NOT NULL", userID).
+			Count(&pinned).Error; err != nil {
+			return err
+		}
+		if pinned >= constants.MaxPinnedPosts {
+			return apperrors.ErrPinLimitReached
+		}
+
+		return tx.Model(post).Update("pin_position", int(pinned)+1).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetPost(userID, postID)
+}
+
+// UnpinPost removes a post from the pinned section
+func (s *PostService) UnpinPost(userID, postID uuid.UUID) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, userID); err != nil {
+			return err
+		}
+
+		post, err := findOwnedPostTx(tx, userID, postID)
+		if err != nil {
+			return err
+		}
+		if post.PinPosition == nil {
+			return apperrors.ErrPostNotPinned
+		}
+
+		if err := tx.Model(post).Update("pin_position", nil).Error; err != nil {
+			return err
+		}
+		return compactPinnedPosts(tx, userID)
+	})
+}
+
+// ReorderPinnedPosts reorders the pinned posts. The request must list exactly the currently pinned posts.
+func (s *PostService) ReorderPinnedPosts(userID uuid.UUID, postIDs []uuid.UUID) ([]models.PostResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, userID); err != nil {
+			return err
+		}
+
+		var pinned []models.Post
+		if err := tx.Where("user_id = ? AND pin_position IS NOT NULL", userID).Find(&pinned).Error; err != nil {
+			return err
+		}
+		if len(pinned) != len(postIDs) {
+			return apperrors.ErrInvalidInput
+		}
+
+		current := make(map[uuid.UUID]bool, len(pinned))
+		for _, post := range pinned {
+			current[post.ID] = true
+		}
+		for _, id := range postIDs {
+			if !current[id] {
+				return apperrors.ErrPostNotPinned
+			}
+			// Guards against the same post being listed twice
+			delete(current, id)
+		}
+
+		for i, id := range postIDs {
+			if err := tx.Model(&models.Post{}).Where("id = ?", id).Update("pin_position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var posts []models.Post
+	if err := s.db.Preload("User").
+		Where("user_id = ? AND pin_position IS NOT NULL", userID).
+		Order("pin_position ASC").
+		Find(&posts).Error; err != nil {
+		return nil, err
+	}
+	return s.buildPostResponses(userID, posts)
+}
+
+func (s *PostService) findPost(postID uuid.UUID) (*models.Post, error) {
+	var post models.Post
+	if err := s.db.Preload("User").First(&post, "id = ?", postID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrPostNotFound
+		}
+		return nil, err
+	}
+	return &post, nil
+}
+
+func (s *PostService) findOwnedPost(userID, postID uuid.UUID) (*models.Post, error) {
+	return findOwnedPostTx(s.db, userID, postID)
+}
+
+func findOwnedPostTx(tx *gorm.DB, userID, postID uuid.UUID) (*models.Post, error) {
+	var post models.Post
+	if err := tx.First(&post, "id = ?", postID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrPostNotFound
+		}
+		return nil, err
+	}
+	if post.UserID != userID {
+		return nil, apperrors.ErrUnauthorizedAction
+	}
+	return &post, nil
+}
+
+// lockUser serializes changes to a user's profile grid
+func lockUser(tx *gorm.DB, userID uuid.UUID) error {
+	var user models.User
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&user, "id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperrors.ErrUserNotFound
+	}
+	return err
+}
+
+// compactPinnedPosts renumbers the remaining pins so positions stay contiguous from 1
+func compactPinnedPosts(tx *gorm.DB, userID uuid.UUID) error {
+	var pinned []models.Post
+	if err := tx.Select("id", "pin_position").
+		Where("user_id = ? AND pin_position IS NOT NULL", userID).
+		Order("pin_position ASC").
+		Find(&pinned).Error; err != nil {
+		return err
+	}
+
+	for i, post := range pinned {
+		if *post.PinPosition == i+1 {
+			continue
+		}
+		if err := tx.Model(&models.Post{}).Where("id = ?", post.ID).Update("pin_position", i+1).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// adjustSharesCount moves the share counter of a post, never below zero
+func adjustSharesCount(tx *gorm.DB, postID uuid.UUID, delta int) error {
+	return tx.Model(&models.Post{}).
+		Where("id = ?", postID).
+		UpdateColumn("shares_count", gorm.Expr("GREATEST(shares_count + ?, 0)", delta)).Error
+}
+
+// syncPostMentions links the mentions of a post caption and notifies the newly mentioned users
+func (s *PostService) syncPostMentions(tx *gorm.DB, post *models.Post) error {
+	added, err := syncMentions(tx, mentionSource{PostID: &post.ID}, post.UserID, post.Caption)
+	if err != nil {
+		return err
+	}
+
+	return s.notificationService.NotifyMany(tx, added, models.Notification{
+		ActorID: post.UserID,
+		Type:    constants.NotificationTypeMention,
+		PostID:  &post.ID,
+		Content: post.Caption,
+	})
+}
+
+// canViewPost applies archive, privacy and audience rules. viewerID is uuid.Nil for anonymous viewers.
+func (s *PostService) canViewPost(viewerID uuid.UUID, post *models.Post) (bool, error) {
+	if viewerID != uuid.Nil && post.UserID == viewerID {
+		return true, nil
+	}
+	if post.IsArchived {
+		return false, nil
+	}
+	if post.IsPublic && !post.User.IsPrivate {
+		return true, nil
+	}
+	if viewerID == uuid.Nil {
+		return false, nil
+	}
+	return s.isFollowing(viewerID, post.UserID)
+}
+
+// visiblePostsScope restricts a query on the posts table to posts the viewer may see,
+// mirroring canViewPost in SQL so lists can be filtered without loading every author
+func visiblePostsScope(viewerID uuid.UUID) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if viewerID == uuid.Nil {
+			return db.Where(
+				"posts.is_archived = ? AND posts.is_public = ? AND posts.user_id IN (SELECT id FROM users WHERE is_private = ? AND deleted_at IS NULL)",
+				false, true, false,
+			)
+		}
+		return db.Where(
+			`(posts.user_id = ? OR (posts.is_archived = ? AND (
+				(posts.is_public = ? AND posts.user_id IN (SELECT id FROM users WHERE is_private = ? AND deleted_at IS NULL))
+				OR posts.user_id IN (SELECT following_id FROM follows WHERE follower_id = ? AND status = ?))))`,
+			viewerID, false, true, false, viewerID, constants.FollowStatusAccepted,
+		)
+	}
+}
+
+// buildPostPage renders a keyset page from posts fetched with limit+1 rows,
+// ordered by (created_at, id)
+func (s *PostService) buildPostPage(viewerID uuid.UUID, posts []models.Post, limit int) (*pagination.Page, error) {
+	hasMore := len(posts) > limit
+	if hasMore {
+		posts = posts[:limit]
+	}
+
+	responses, err := s.buildPostResponses(viewerID, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &pagination.Page{Items: responses, HasMore: hasMore}
+	if hasMore {
+		last := posts[len(posts)-1]
+		page.NextCursor = pagination.NewCursor(last.CreatedAt, last.ID).Encode()
+	}
+	return page, nil
+}
+
+// loadPostsInOrder loads posts by ID and returns them in the order of ids, skipping missing
+// ones and those excluded by scopes
+func (s *PostService) loadPostsInOrder(ids []uuid.UUID, scopes ...func(*gorm.DB) *gorm.DB) ([]models.Post, error) {
+	if len(ids) == 0 {
+		return []models.Post{}, nil
+	}
+
+	var posts []models.Post
+	if err := s.db.Scopes(scopes...).Preload("User").Where("posts.id IN ?", ids).Find(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]models.Post, len(posts))
+	for _, post := range posts {
+		byID[post.ID] = post
+	}
+
+	ordered := make([]models.Post, 0, len(posts))
+	for _, id := range ids {
+		if post, ok := byID[id]; ok {
+			ordered = append(ordered, post)
+		}
+	}
+	return ordered, nil
+}
+
+func (s *PostService) isFollowing(followerID, followingID uuid.UUID) (bool, error) {
+	var count int64
+	err := s.db.Model(&models.Follow{}).
+		Where("follower_id = ? AND following_id = ? AND status = ?", followerID, followingID, constants.FollowStatusAccepted).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// buildPostResponses converts posts, fills viewer specific fields in batch and embeds
+// the original of reposts and quote posts when the viewer can still see it
+func (s *PostService) buildPostResponses(viewerID uuid.UUID, posts []models.Post) ([]models.PostResponse, error) {
+	responses, err := s.buildShallowPostResponses(viewerID, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	var originalIDs []uuid.UUID
+	seen := map[uuid.UUID]bool{}
+	for _, post := range posts {
+		if post.OriginalPostID != nil && !seen[*post.OriginalPostID] {
+			seen[*post.OriginalPostID] = true
+			originalIDs = append(originalIDs, *post.OriginalPostID)
+		}
+	}
+	if len(originalIDs) == 0 {
+		return responses, nil
+	}
+
+	// Deleted originals are excluded by the soft delete scope, hidden ones by the visibility scope
+	var originals []models.Post
+	if err := s.db.Preload("User").
+		Scopes(visiblePostsScope(viewerID)).
+		Where("posts.id IN ?", originalIDs).
+		Find(&originals).Error; err != nil {
+		return nil, err
+	}
+	originalResponses, err := s.buildShallowPostResponses(viewerID, originals)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]models.PostResponse, len(originalResponses))
+	for _, response := range originalResponses {
+		byID[response.ID] = response
+	}
+
+	for i, post := range posts {
+		if post.OriginalPostID == nil {
+			continue
+		}
+		if original, ok := byID[*post.OriginalPostID]; ok {
+			responses[i].OriginalPost = &original
+		} else {
+			responses[i].OriginalUnavailable = true
+		}
+	}
+	return responses, nil
+}
+
+// buildShallowPostResponses converts posts and fills viewer specific fields in batch
+func (s *PostService) buildShallowPostResponses(viewerID uuid.UUID, posts []models.Post) ([]models.PostResponse, error) {
+	responses := make([]models.PostResponse, 0, len(posts))
+	if len(posts) == 0 {
+		return responses, nil
+	}
+
+	ids := make([]uuid.UUID, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+
+	reactionCounts, viewerReactions, err := loadReactionSummaries(s.db, viewerID, ReactionTargetPost, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := map[uuid.UUID]bool{}
+	if viewerID != uuid.Nil {
+		var savedIDs []uuid.UUID
+		if err := s.db.Model(&models.SavedPost{}).
+			Where("user_id = ? AND post_id IN ?", viewerID, ids).
+			Pluck("post_id", &savedIDs).Error; err != nil {
+			return nil, err
+		}
+		for _, id := range savedIDs {
+			saved[id] = true
+		}
+	}
+
+	polls, err := loadPollResponses(s.db, viewerID, posts)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions, err := loadMentions(s.db, "post_id", ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range posts {
+		posts[i].IsLiked = viewerReactions[posts[i].ID] != ""
+		posts[i].IsSaved = saved[posts[i].ID]
+		response := posts[i].ToResponse()
+		response.Poll = polls[posts[i].ID]
+		response.ReactionCounts = reactionCounts[posts[i].ID]
+		response.ViewerReaction = viewerReactions[posts[i].ID]
+		response.Mentions = mentions[posts[i].ID]
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"social-media-backend/internal/models"
+)
+
+// Candidate sources of the ranked feed
+const (
+	CandidateFollowed = "followed"
+	CandidateHashtag  = "hashtag"
+	CandidatePopular  = "popular"
+)
+
+// RankingCandidate is a post considered for the ranked feed together with the signals
+// a Scorer may use
+type RankingCandidate struct {
+	Post     *models.Post
+	Source   string
+	Affinity float64 // Interactions of the viewer with the author, 0 for strangers
+	Now      time.Time
+}
+
+// Scorer rates how relevant a candidate is to the viewer. Higher scores rank first.
+type Scorer interface {
+	Score(candidate *RankingCandidate) float64
+}
+
+// ScorerFunc adapts a function to the Scorer interface
+type ScorerFunc func(candidate *RankingCandidate) float64
+
+func (f ScorerFunc) Score(candidate *RankingCandidate) float64 {
+	return f(candidate)
+}
+
+// WeightedScorer multiplies a recency decay by boosts for engagement and affinity:
+//
+//	score = 0.5^(age/RecencyHalfLife) * (1 + EngagementWeight*ln(1+rate)) * (1 + AffinityWeight*affinity)
+//
+// where rate is likes plus weighted comments and shares per hour since posting.
+type WeightedScorer struct {
+	RecencyHalfLife  time.Duration
+	EngagementWeight float64
+	CommentWeight    float64 // A comment counts as this many likes
+	ShareWeight      float64 // A share counts as this many likes
+	AffinityWeight   float64
+}
+
+// NewWeightedScorer returns a WeightedScorer with the default weights
+func NewWeightedScorer() *WeightedScorer {
+	return &WeightedScorer{
+		RecencyHalfLife:  12 * time.Hour,
+		EngagementWeight: 0.5,
+		CommentWeight:    2,
+		ShareWeight:      3,
+		AffinityWeight:   0.3,
+	}
+}
+
+func (s *WeightedScorer) Score(candidate *RankingCandidate) float64 {
+	post := candidate.Post
+	age := candidate.Now.Sub(post.CreatedAt)
+	if age < 0 {
+		age = 0
+	}
+
+	recency := math.Pow(0.5, age.Hours()/s.RecencyHalfLife.Hours())
+
+	interactions := float64(post.LikesCount) +
+		s.CommentWeight*float64(post.CommentsCount) +
+		s.ShareWeight*float64(post.SharesCount)
+	// Young posts are given an hour so a couple of early likes do not dominate
+	rate := interactions / math.Max(age.Hours(), 1)
+
+	return recency *
+		(1 + s.EngagementWeight*math.Log1p(rate)) *
+		(1 + s.AffinityWeight*candidate.Affinity)
+}
+
+// applyDiversity reorders ranked candidates so that no author has more than maxRun posts
+// in a row. A post that would break the rule is deferred to the first position where it
+// fits, keeping the ranking otherwise intact. Posts that cannot be placed anywhere, when
+// one author makes up the rest of the list, are appended at the end.
+func applyDiversity(ranked []*RankingCandidate, maxRun int) []*RankingCandidate {
+	result := make([]*RankingCandidate, 0, len(ranked))
+	pending := append([]*RankingCandidate(nil), ranked...)
+
+	fits := func(authorID uuid.UUID) bool {
+		if len(result) < maxRun {
+			return true
+		}
+		for _, placed := range result[len(result)-maxRun:] {
+			if placed.Post.UserID != authorID {
+				return true
+			}
+		}
+		return false
+	}
+
+	for len(pending) > 0 {
+		placed := false
+		for i, candidate := range pending {
+			if fits(candidate.Post.UserID) {
+				result = append(result, candidate)
+				pending = append(pending[:i], pending[i+1:]...)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			return append(result, pending...)
+		}
+	}
+	return result
+}
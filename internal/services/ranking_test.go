@@ -0,0 +1,137 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"social-media-backend/internal/models"
+)
+
+func TestWeightedScorer(t *testing.T) {
+	scorer := NewWeightedScorer()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	score := func(age time.Duration, likes, comments, shares int, affinity float64) float64 {
+		return scorer.Score(&RankingCandidate{
+			Post: &models.Post{
+				CreatedAt:     now.Add(-age),
+				LikesCount:    likes,
+				CommentsCount: comments,
+				SharesCount:   shares,
+			},
+			Affinity: affinity,
+			Now:      now,
+		})
+	}
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"fresh post without engagement", score(0, 0, 0, 0, 0), 1},
+		{"future posts count as fresh", score(-time.Hour, 0, 0, 0, 0), 1},
+		{"one half-life halves the score", score(12*time.Hour, 0, 0, 0, 0), 0.5},
+		{"two half-lives quarter the score", score(24*time.Hour, 0, 0, 0, 0), 0.25},
+		{"affinity boost", score(0, 0, 0, 0, 2), 1.6},
+		// 1 like + 2*1 comment + 3*1 share = 6 interactions within the first hour
+		{"engagement rate", score(30*time.Minute, 1, 1, 1, 0), math.Pow(0.5, 0.5/12) * (1 + 0.5*math.Log1p(6))},
+		// 24 likes over 12 hours is a rate of 2 per hour
+		{"engagement is per hour", score(12*time.Hour, 24, 0, 0, 0), 0.5 * (1 + 0.5*math.Log1p(2))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if math.Abs(tt.got-tt.want) > 1e-9 {
+				t.Errorf("score = %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeightedScorerOrdering(t *testing.T) {
+	scorer := NewWeightedScorer()
+	now := time.Now()
+	candidate := func(age time.Duration, likes int, affinity float64) *RankingCandidate {
+		return &RankingCandidate{
+			Post:     &models.Post{CreatedAt: now.Add(-age), LikesCount: likes},
+			Affinity: affinity,
+			Now:      now,
+		}
+	}
+
+	if scorer.Score(candidate(time.Hour, 0, 0)) <= scorer.Score(candidate(10*time.Hour, 0, 0)) {
+		t.Error("newer posts should outrank older ones")
+	}
+	if scorer.Score(candidate(time.Hour, 50, 0)) <= scorer.Score(candidate(time.Hour, 5, 0)) {
+		t.Error("more engaged posts should outrank less engaged ones")
+	}
+	if scorer.Score(candidate(time.Hour, 0, 3)) <= scorer.Score(candidate(time.Hour, 0, 0)) {
+		t.Error("posts of closer authors should outrank strangers")
+	}
+}
+
+func TestApplyDiversity(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	names := map[uuid.UUID]string{a: "a", b: "b", c: "c"}
+
+	tests := []struct {
+		name    string
+		authors []uuid.UUID
+		maxRun  int
+		want    string
+	}{
+		{"empty", nil, 2, ""},
+		{"already diverse", []uuid.UUID{a, b, a, b}, 1, "abab"},
+		{"runs up to the limit are kept", []uuid.UUID{a, a, b, b}, 2, "aabb"},
+		{"long run is broken up", []uuid.UUID{a, a, a, b, c}, 2, "aabac"},
+		{"deferred post takes the first place it fits", []uuid.UUID{a, a, a, a, b, b}, 2, "aabaab"},
+		{"single author leftovers are appended", []uuid.UUID{a, a, a, a}, 2, "aaaa"},
+		{"leftovers after others run out", []uuid.UUID{a, a, a, a, a, b}, 1, "abaaaa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranked := make([]*RankingCandidate, len(tt.authors))
+			for i, author := range tt.authors {
+				ranked[i] = &RankingCandidate{Post: &models.Post{ID: uuid.New(), UserID: author}}
+			}
+
+			result := applyDiversity(ranked, tt.maxRun)
+			if len(result) != len(ranked) {
+				t.Fatalf("got %d candidates, want %d", len(result), len(ranked))
+			}
+			got := ""
+			for _, candidate := range result {
+				got += names[candidate.Post.UserID]
+			}
+			if got != tt.want {
+				t.Errorf("order = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyDiversityKeepsRankWithinAuthor(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	ranked := []*RankingCandidate{
+		{Post: &models.Post{ID: uuid.New(), UserID: a}},
+		{Post: &models.Post{ID: uuid.New(), UserID: a}},
+		{Post: &models.Post{ID: uuid.New(), UserID: a}},
+		{Post: &models.Post{ID: uuid.New(), UserID: b}},
+	}
+
+	result := applyDiversity(ranked, 2)
+	want := []*RankingCandidate{ranked[0], ranked[1], ranked[3], ranked[2]}
+	for i := range want {
+		if result[i] != want[i] {
+			t.Fatalf("position %d holds the wrong candidate", i)
+		}
+	}
+	if ranked[2].Post.UserID != a {
+		t.Error("the input was modified")
+	}
+}
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/pkg/constants"
+	apperrors "social-media-backend/pkg/errors"
+	"social-media-backend/pkg/pagination"
+)
+
+// Kinds of content that can receive reactions
+const (
+	ReactionTargetPost    = "post"
+	ReactionTargetComment = "comment"
+	ReactionTargetMessage = "message"
+)
+
+// reactionColumns maps a target kind to its column on the likes table
+var reactionColumns = map[string]string{
+	ReactionTargetPost:    "post_id",
+	ReactionTargetComment: "comment_id",
+	ReactionTargetMessage: "message_id",
+}
+
+// reactionCounters maps a target kind to the table whose likes_count tracks its reactions.
+// Messages have no counter.
+var reactionCounters = map[string]string{
+	ReactionTargetPost:    "posts",
+	ReactionTargetComment: "comments",
+}
+
+type ReactionService struct {
+	db                  *gorm.DB
+	postService         *PostService
+	notificationService *NotificationService
+}
+
+func NewReactionService(db *gorm.DB, postService *PostService, notificationService *NotificationService) *ReactionService {
+	return &ReactionService{db: db, postService: postService, notificationService: notificationService}
+}
+
+// reactionTarget is a piece of content the user is allowed to react to
+type reactionTarget struct {
+	kind      string
+	id        uuid.UUID
+	ownerID   uuid.UUID
+	postID    *uuid.UUID
+	commentID *uuid.UUID
+}
+
+// React sets the user's reaction on a target, replacing any previous reaction type.
+// LikesCount only moves when the user had not reacted before.
+func (s *ReactionService) React(userID uuid.UUID, kind string, targetID uuid.UUID, reactionType string) error {
+	target, err := s.resolveTarget(userID, kind, targetID)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		column := reactionColumns[kind]
+
+		var result struct{ Inserted bool }
+		if err := tx.Raw(fmt.Sprintf(`
+			INSERT INTO likes (id, user_id, %[1]s, type, created_at) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (user_id, %[1]s) WHERE %[1]s IS NOT NULL
+			DO UPDATE SET type = EXCLUDED.type
+			RETURNING (xmax = 0) AS inserted`, column),
+			uuid.New(), userID, targetID, reactionType, time.Now(),
+		).Scan(&result).Error; err != nil {
+			return err
+		}
+		if !result.Inserted {
+			return nil
+		}
+
+		if err := adjustLikesCount(tx, kind, targetID, 1); err != nil {
+			return err
+		}
+		if kind == ReactionTargetMessage {
+			return nil
+		}
+
+		return s.notificationService.Notify(tx, &models.Notification{
+			UserID:    target.ownerID,
+			ActorID:   userID,
+			Type:      constants.NotificationTypeLike,
+			PostID:    target.postID,
+			CommentID: target.commentID,
+			Content:   reactionType,
+		})
+	})
+}
+
+// RemoveReaction deletes the user's reaction on a target
+func (s *ReactionService) RemoveReaction(userID uuid.UUID, kind string, targetID uuid.UUID) error {
+	if _, err := s.resolveTarget(userID, kind, targetID); err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where(fmt.Sprintf("user_id = ? AND %s = ?", reactionColumns[kind]), userID, targetID).
+			Delete(&models.Like{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperrors.ErrNotLiked
+		}
+		return adjustLikesCount(tx, kind, targetID, -1)
+	})
+}
+
+// Like adds a plain like to the single target of req. The partial unique indexes make the
+// insert a no-op for a second like, so concurrent double taps move LikesCount only once.
+// With idempotent set, liking something already liked succeeds instead of returning ErrAlreadyLiked.
+func (s *ReactionService) Like(userID uuid.UUID, req *models.LikeRequest, idempotent bool) (*models.LikeStatusResponse, error) {
+	kind, targetID, err := likeTarget(req)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.resolveTarget(userID, kind, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var result struct{ Inserted bool }
+		if err := tx.Raw(fmt.Sprintf(`
+			INSERT INTO likes (id, user_id, %[1]s, type, created_at) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (user_id, %[1]s) WHERE %[1]s IS NOT NULL DO NOTHING
+			RETURNING true AS inserted`, reactionColumns[kind]),
+			uuid.New(), userID, targetID, constants.ReactionLike, time.Now(),
+		).Scan(&result).Error; err != nil {
+			return err
+		}
+		if !result.Inserted {
+			if idempotent {
+				return nil
+			}
+			return apperrors.ErrAlreadyLiked
+		}
+
+		if err := adjustLikesCount(tx, kind, targetID, 1); err != nil {
+			return err
+		}
+		return s.notificationService.Notify(tx, &models.Notification{
+			UserID:    target.ownerID,
+			ActorID:   userID,
+			Type:      constants.NotificationTypeLike,
+			PostID:    target.postID,
+			CommentID: target.commentID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.likeStatus(kind, targetID, true)
+}
+
+// Unlike removes the user's like or reaction from the single target of req.
+// With idempotent set, unliking something not liked succeeds instead of returning ErrNotLiked.
+func (s *ReactionService) Unlike(userID uuid.UUID, req *models.LikeRequest, idempotent bool) (*models.LikeStatusResponse, error) {
+	kind, targetID, err := likeTarget(req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.RemoveReaction(userID, kind, targetID)
+	if err != nil && !(idempotent && errors.Is(err, apperrors.ErrNotLiked)) {
+		return nil, err
+	}
+
+	return s.likeStatus(kind, targetID, false)
+}
+
+func (s *ReactionService) likeStatus(kind string, targetID uuid.UUID, liked bool) (*models.LikeStatusResponse, error) {
+	status := &models.LikeStatusResponse{Liked: liked}
+	if err := s.db.Table(reactionCounters[kind]).
+		Select("likes_count").
+		Where("id = ?", targetID).
+		Scan(&status.LikesCount).Error; err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// likeTarget validates that exactly one of the request's targets is set
+func likeTarget(req *models.LikeRequest) (string, uuid.UUID, error) {
+	switch {
+	case req.PostID != nil && req.CommentID == nil:
+		return ReactionTargetPost, *req.PostID, nil
+	case req.CommentID != nil && req.PostID == nil:
+		return ReactionTargetComment, *req.CommentID, nil
+	}
+	return "", uuid.Nil, apperrors.ErrInvalidLikeTarget
+}
+
+// ListReactions lists who reacted to a target, newest first, optionally filtered by type
+func (s *ReactionService) ListReactions(userID uuid.UUID, kind string, targetID uuid.UUID, reactionType string, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	if _, err := s.resolveTarget(userID, kind, targetID); err != nil {
+		return nil, err
+	}
+
+	query := s.db.Preload("User").Where(fmt.Sprintf("%s = ?", reactionColumns[kind]), targetID)
+	if reactionType != "" {
+		query = query.Where("type = ?", reactionType)
+	}
+	if cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	var likes []models.Like
+	if err := query.Order("created_at DESC, id DESC").Limit(limit + 1).Find(&likes).Error; err != nil {
+		return nil, err
+	}
+
+	hasMore := len(likes) > limit
+	if hasMore {
+		likes = likes[:limit]
+	}
+
+	reactions := make([]models.ReactionResponse, len(likes))
+	for i, like := range likes {
+		reactions[i] = models.ReactionResponse{
+			ID:        like.ID,
+			User:      like.User.ToResponse(),
+			Type:      like.Type,
+			CreatedAt: like.CreatedAt,
+		}
+	}
+
+	page := &pagination.Page{Items: reactions, HasMore: hasMore}
+	if hasMore {
+		last := likes[len(likes)-1]
+		page.NextCursor = pagination.NewCursor(last.CreatedAt, last.ID).Encode()
+	}
+	return page, nil
+}
+
+// resolveTarget loads the target and checks the user can see it
+func (s *ReactionService) resolveTarget(userID uuid.UUID, kind string, targetID uuid.UUID) (*reactionTarget, error) {
+	switch kind {
+	case ReactionTargetPost:
+		post, err := s.postService.GetPost(userID, targetID)
+		if err != nil {
+			return nil, err
+		}
+		return &reactionTarget{kind: kind, id: targetID, ownerID: post.User.ID, postID: &post.ID}, nil
+
+	case ReactionTargetComment:
+		var comment models.Comment
+		if err := s.db.First(&comment, "id = ?", targetID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, apperrors.ErrCommentNotFound
+			}
+			return nil, err
+		}
+		if _, err := s.postService.GetPost(userID, comment.PostID); err != nil {
+			return nil, err
+		}
+		return &reactionTarget{kind: kind, id: targetID, ownerID: comment.UserID, postID: &comment.PostID, commentID: &comment.ID}, nil
+
+	case ReactionTargetMessage:
+		message, err := findVisibleMessage(s.db, userID, targetID)
+		if err != nil {
+			return nil, err
+		}
+		return &reactionTarget{kind: kind, id: targetID, ownerID: message.SenderID}, nil
+	}
+
+	return nil, apperrors.ErrInvalidInput
+}
+
+// adjustLikesCount moves the reaction counter of a post or comment, never below zero
+func adjustLikesCount(tx *gorm.DB, kind string, targetID uuid.UUID, delta int) error {
+	table, ok := reactionCounters[kind]
+	if !ok {
+		return nil
+	}
+	return tx.Table(table).
+		Where("id = ?", targetID).
+		UpdateColumn("likes_count", gorm.Expr("GREATEST(likes_count + ?, 0)", delta)).Error
+}
+
+// loadReactionSummaries returns per type reaction counts and the viewer's own reaction for each target
+func loadReactionSummaries(db *gorm.DB, viewerID uuid.UUID, kind string, ids []uuid.UUID) (map[uuid.UUID]map[string]int, map[uuid.UUID]string, error) {
+	counts := map[uuid.UUID]map[string]int{}
+	viewer := map[uuid.UUID]string{}
+	if len(ids) == 0 {
+		return counts, viewer, nil
+	}
+	column := reactionColumns[kind]
+
+	var rows []struct {
+		TargetID uuid.UUID
+		Type     string
+		Count    int
+	}
+	if err := db.Model(&models.Like{}).
+		Select(fmt.Sprintf("%s AS target_id, type, COUNT(*) AS count", column)).
+		Where(fmt.Sprintf("%s IN ?", column), ids).
+		Group(fmt.Sprintf("%s, type", column)).
+		Scan(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+	for _, row := range rows {
+		if counts[row.TargetID] == nil {
+			counts[row.TargetID] = map[string]int{}
+		}
+		counts[row.TargetID][row.Type] = row.Count
+	}
+
+	if viewerID != uuid.Nil {
+		var own []struct {
+			TargetID uuid.UUID
+			Type     string
+		}
+		if err := db.Model(&models.Like{}).
+			Select(fmt.Sprintf("%s AS target_id, type", column)).
+			Where(fmt.Sprintf("user_id = ? AND %s IN ?", column), viewerID, ids).
+			Scan(&own).Error; err != nil {
+			return nil, nil, err
+		}
+		for _, row := range own {
+			viewer[row.TargetID] = row.Type
+		}
+	}
+
+	return counts, viewer, nil
+}
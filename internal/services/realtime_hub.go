@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/pkg/constants"
+)
+
+// RealtimeHub delivers events to the WebSocket connections of users. Events are published
+// on a Redis channel that every instance subscribes to, so each instance delivers them to
+// its own connections. Without Redis, events only reach connections on this instance.
+type RealtimeHub struct {
+	db  *gorm.DB
+	rdb *redis.Client
+
+	mu      sync.RWMutex
+	clients map[uuid.UUID]map[*RealtimeClient]struct{}
+}
+
+func NewRealtimeHub(db *gorm.DB, rdb *redis.Client) *RealtimeHub {
+	return &RealtimeHub{db: db, rdb: rdb, clients: make(map[uuid.UUID]map[*RealtimeClient]struct{})}
+}
+
+// RealtimeClient is one WebSocket connection of a user. Events are queued on a bounded
+// buffer; a client that falls behind is dropped and has to reconnect and resume.
+type RealtimeClient struct {
+	UserID uuid.UUID
+
+	events chan []byte
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Events yields the encoded events to write to the connection
+func (c *RealtimeClient) Events() <-chan []byte {
+	return c.events
+}
+
+// Done is closed once the hub dropped the client
+func (c *RealtimeClient) Done() <-chan struct{} {
+	return c.done
+}
+
+// Send queues an event for this connection only
+func (c *RealtimeClient) Send(event models.RealtimeEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("realtime: encode event: %v", err)
+		return
+	}
+	c.enqueue(payload)
+}
+
+func (c *RealtimeClient) enqueue(payload []byte) {
+	select {
+	case <-c.done:
+	case c.events <- payload:
+	default:
+		c.close()
+	}
+}
+
+func (c *RealtimeClient) close() {
+	c.once.Do(func() { close(c.done) })
+}
+
+// realtimeEnvelope is an event on the Redis channel with the users it is for
+type realtimeEnvelope struct {
+	UserIDs []uuid.UUID          `json:"user_ids"`
+	Event   models.RealtimeEvent `json:"event"`
+}
+
+// Register adds a connection of the user
+func (h *RealtimeHub) Register(userID uuid.UUID) *RealtimeClient {
+	client := &RealtimeClient{
+		UserID: userID,
+		events: make(chan []byte, constants.RealtimeSendBuffer),
+		done:   make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.clients[userID] == nil {
+		h.clients[userID] = make(map[*RealtimeClient]struct{})
+	}
+	h.clients[userID][client] = struct{}{}
+	return client
+}
+
+// Unregister removes a connection once it is closed
+func (h *RealtimeHub) Unregister(client *RealtimeClient) {
+	client.close()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients[client.UserID], client)
+	if len(h.clients[client.UserID]) == 0 {
+		delete(h.clients, client.UserID)
+	}
+}
+
+// Publish sends an event to every connection of the users, on all instances. Delivery is
+// best effort: clients catch up on messages by resuming after reconnecting.
+func (h *RealtimeHub) Publish(ctx context.Context, userIDs []uuid.UUID, event models.RealtimeEvent) {
+	if h == nil || len(userIDs) == 0 {
+		return
+	}
+
+	if h.rdb != nil {
+		payload, err := json.Marshal(realtimeEnvelope{UserIDs: userIDs, Event: event})
+		if err != nil {
+			log.Printf("realtime: encode event: %v", err)
+			return
+		}
+		err = h.rdb.Publish(ctx, constants.RealtimeChannel, payload).Err()
+		if err == nil {
+			return
+		}
+		log.Printf("realtime: publish: %v", err)
+	}
+
+	// Redis is unavailable, so at least reach the connections on this instance
+	h.deliver(userIDs, event)
+}
+
+// PublishToConversation sends an event to the participants of a conversation, leaving out
+// exceptID when set
+func (h *RealtimeHub) PublishToConversation(conversationID uuid.UUID, exceptID uuid.UUID, event models.RealtimeEvent) {
+	if h == nil {
+		return
+	}
+
+	var userIDs []uuid.UUID
+	if err := h.db.Model(&models.ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id <> ?", conversationID, exceptID).
+		Pluck("user_id", &userIDs).Error; err != nil {
+		log.Printf("realtime: conversation %s participants: %v", conversationID, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.RealtimeWriteTimeout)
+	defer cancel()
+	h.Publish(ctx, userIDs, event)
+}
+
+// Run relays events from the Redis channel to the connections on this instance until ctx
+// is done. The subscription reconnects on its own when Redis goes away.
+func (h *RealtimeHub) Run(ctx context.Context) {
+	if h.rdb == nil {
+		return
+	}
+
+	pubsub := h.rdb.Subscribe(ctx, constants.RealtimeChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			var envelope realtimeEnvelope
+			if err := json.Unmarshal([]byte(message.Payload), &envelope); err != nil {
+				log.Printf("realtime: decode event: %v", err)
+				continue
+			}
+			h.deliver(envelope.UserIDs, envelope.Event)
+		}
+	}
+}
+
+// deliver queues an event on the local connections of the users
+func (h *RealtimeHub) deliver(userIDs []uuid.UUID, event models.RealtimeEvent) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("realtime: encode event: %v", err)
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, userID := range userIDs {
+		for client := range h.clients[userID] {
+			client.enqueue(payload)
+		}
+	}
+}
+
+// publishMessage announces a new message to the conversation, including the sender's other
+// connections
+func (h *RealtimeHub) publishMessage(message *models.MessageResponse) {
+	h.PublishToConversation(message.ConversationID, uuid.Nil, models.RealtimeEvent{
+		Type:           constants.RealtimeEventMessage,
+		ConversationID: &message.ConversationID,
+		Data:           message,
+	})
+}
+
+// publishMessages announces messages created inside a transaction once it committed
+func (h *RealtimeHub) publishMessages(messages []*models.Message) {
+	if h == nil || len(messages) == 0 {
+		return
+	}
+
+	ids := make([]uuid.UUID, len(messages))
+	for i, message := range messages {
+		ids[i] = message.ID
+	}
+	var loaded []models.Message
+	if err := h.db.Preload("Sender").
+		Where("id IN ?", ids).
+		Order("created_at ASC, id ASC").
+		Find(&loaded).Error; err != nil {
+		log.Printf("realtime: load messages: %v", err)
+		return
+	}
+	responses, err := buildMessageResponses(h.db, uuid.Nil, loaded)
+	if err != nil {
+		log.Printf("realtime: render messages: %v", err)
+		return
+	}
+	for i := range responses {
+		h.publishMessage(&responses[i])
+	}
+}
+
+// publishReadReceipt announces a participant's new read cursor to the conversation
+func (h *RealtimeHub) publishReadReceipt(conversationID, userID, messageID uuid.UUID, readAt time.Time) {
+	h.PublishToConversation(conversationID, uuid.Nil, models.RealtimeEvent{
+		Type:           constants.RealtimeEventRead,
+		ConversationID: &conversationID,
+		Data: models.ReadReceipt{
+			UserID:            userID,
+			LastReadMessageID: &messageID,
+			LastReadAt:        &readAt,
+		},
+	})
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/pkg/constants"
+	apperrors "social-media-backend/pkg/errors"
+)
+
+type ShareService struct {
+	db                  *gorm.DB
+	postService         *PostService
+	notificationService *NotificationService
+}
+
+func NewShareService(db *gorm.DB, postService *PostService, notificationService *NotificationService) *ShareService {
+	return &ShareService{db: db, postService: postService, notificationService: notificationService}
+}
+
+// Repost re-shares a post as is. Reposting a repost re-shares the post it points to.
+func (s *ShareService) Repost(userID, postID uuid.UUID) (*models.PostResponse, error) {
+	original, err := s.shareableOriginal(userID, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	repost := models.Post{
+		UserID:         userID,
+		OriginalPostID: &original.ID,
+		ShareType:      constants.ShareTypeRepost,
+		IsPublic:       true,
+	}
+	if err := s.createShare(&repost, original); err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperrors.ErrAlreadyReposted
+		}
+		return nil, err
+	}
+
+	return s.postService.GetPost(userID, repost.ID)
+}
+
+// UndoRepost removes the user's repost. postID may be the original post or the repost itself.
+func (s *ShareService) UndoRepost(userID, postID uuid.UUID) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var repost models.Post
+		err := tx.Where("user_id = ? AND share_type = ? AND (original_post_id = ? OR id = ?)",
+			userID, constants.ShareTypeRepost, postID, postID).
+			First(&repost).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperrors.ErrNotReposted
+			}
+			return err
+		}
+
+		if err := tx.Delete(&repost).Error; err != nil {
+			return err
+		}
+		if repost.PinPosition != nil {
+			if err := compactPinnedPosts(tx, userID); err != nil {
+				return err
+			}
+		}
+		return adjustSharesCount(tx, *repost.OriginalPostID, -1)
+	})
+}
+
+// QuotePost shares a post with a new caption
+func (s *ShareService) QuotePost(userID, postID uuid.UUID, req *models.QuotePostRequest) (*models.PostResponse, error) {
+	original, err := s.shareableOriginal(userID, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := models.Post{
+		UserID:         userID,
+		Caption:        req.Caption,
+		MediaType:      constants.PostTypeText,
+		Location:       req.Location,
+		OriginalPostID: &original.ID,
+		ShareType:      constants.ShareTypeQuote,
+		IsPublic:       true,
+	}
+	if req.IsPublic != nil {
+		quote.IsPublic = *req.IsPublic
+	}
+
+	if err := s.createShare(&quote, original); err != nil {
+		return nil, err
+	}
+
+	return s.postService.GetPost(userID, quote.ID)
+}
+
+func (s *ShareService) createShare(share *models.Post, original *models.Post) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(share).Error; err != nil {
+			return err
+		}
+		if err := syncPostHashtags(tx, share.ID, share.Caption); err != nil {
+			return err
+		}
+		if err := s.postService.syncPostMentions(tx, share); err != nil {
+			return err
+		}
+		if err := adjustSharesCount(tx, original.ID, 1); err != nil {
+			return err
+		}
+
+		return s.notificationService.Notify(tx, &models.Notification{
+			UserID:  original.UserID,
+			ActorID: share.UserID,
+			Type:    constants.NotificationTypeShare,
+			PostID:  &share.ID,
+			Content: share.Caption,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.postService.fanout.Publish(share)
+	return nil
+}
+
+// shareableOriginal resolves the post to share and checks the user may share it.
+// Only posts everyone can see are shareable, except by their own author.
+func (s *ShareService) shareableOriginal(userID, postID uuid.UUID) (*models.Post, error) {
+	post, err := s.postService.findPost(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if post.ShareType == constants.ShareTypeRepost && post.OriginalPostID != nil {
+		if post, err = s.postService.findPost(*post.OriginalPostID); err != nil {
+			return nil, err
+		}
+	}
+
+	allowed, err := s.postService.canViewPost(userID, post)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, apperrors.ErrPostNotFound
+	}
+
+	if post.UserID != userID && (!post.IsPublic || post.User.IsPrivate || post.IsArchived) {
+		return nil, apperrors.ErrCannotSharePost
+	}
+	return post, nil
+}
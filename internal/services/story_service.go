@@ -0,0 +1,542 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/storage"
+	"social-media-backend/pkg/constants"
+	apperrors "social-media-backend/pkg/errors"
+	"social-media-backend/pkg/pagination"
+)
+
+type StoryService struct {
+	db                  *gorm.DB
+	storage             storage.Storage
+	notificationService *NotificationService
+	messageService      *MessageService
+}
+
+func NewStoryService(db *gorm.DB, storage storage.Storage, notificationService *NotificationService, messageService *MessageService) *StoryService {
+	return &StoryService{db: db, storage: storage, notificationService: notificationService, messageService: messageService}
+}
+
+// CreateStory uploads the story media and publishes a story that expires after StoryDuration.
+// Users mentioned in the caption are notified.
+func (s *StoryService) CreateStory(ctx context.Context, userID uuid.UUID, req *models.CreateStoryRequest, file *multipart.FileHeader) (*models.StoryResponse, error) {
+	ext, err := validateMediaUpload(file, req.MediaType)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	key := fmt.Sprintf("stories/%s/%s%s", userID, uuid.New(), ext)
+	url, err := s.storage.Save(ctx, key, src)
+	if err != nil {
+		log.Printf("story upload: %v", err)
+		return nil, apperrors.ErrFileUploadFailed
+	}
+
+	story := models.Story{
+		UserID:    userID,
+		MediaURL:  url,
+		MediaKey:  key,
+		MediaType: req.MediaType,
+		Caption:   req.Caption,
+		ExpiresAt: time.Now().Add(constants.StoryDuration * time.Hour),
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&story).Error; err != nil {
+			return err
+		}
+
+		added, err := syncMentions(tx, mentionSource{StoryID: &story.ID}, userID, story.Caption)
+		if err != nil {
+			return err
+		}
+		tagged, err := createStickers(tx, &story, req.Stickers)
+		if err != nil {
+			return err
+		}
+		for _, id := range tagged {
+			if !slices.Contains(added, id) {
+				added = append(added, id)
+			}
+		}
+		return s.notificationService.NotifyMany(tx, added, models.Notification{
+			ActorID: userID,
+			Type:    constants.NotificationTypeMention,
+			StoryID: &story.ID,
+			Content: story.Caption,
+		})
+	})
+	if err != nil {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			log.Printf("story upload cleanup: %v", err)
+		}
+		return nil, err
+	}
+
+	return s.GetStory(userID, story.ID)
+}
+
+// GetStory returns a live story if the viewer is allowed to see it. Authors can still see
+// their own stories until the sweeper removes them.
+func (s *StoryService) GetStory(viewerID, storyID uuid.UUID) (*models.StoryResponse, error) {
+	story, err := s.findViewableStory(viewerID, storyID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := s.buildStoryResponses(viewerID, []models.Story{*story})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// RecordView marks a story as seen by the viewer. Repeated views are ignored, so
+// ViewsCount counts unique viewers; authors viewing their own stories are not counted.
+func (s *StoryService) RecordView(viewerID, storyID uuid.UUID) error {
+	story, err := s.findViewableStory(viewerID, storyID)
+	if err != nil {
+		return err
+	}
+	if story.UserID == viewerID {
+		return nil
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.StoryView{StoryID: story.ID, UserID: viewerID})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Model(&models.Story{}).
+			Where("id = ?", story.ID).
+			UpdateColumn("views_count", gorm.Expr("views_count + 1")).Error
+	})
+}
+
+// GetStoryViewers lists who viewed one of the user's stories, most recent first. The list
+// stays available until StoryViewersWindow after the story expired.
+func (s *StoryService) GetStoryViewers(userID, storyID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	var story models.Story
+	if err := s.db.Unscoped().First(&story, "id = ?", storyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrStoryNotFound
+		}
+		return nil, err
+	}
+	if story.UserID != userID {
+		return nil, apperrors.ErrUnauthorizedAction
+	}
+	if time.Now().After(story.ExpiresAt.Add(constants.StoryViewersWindow)) {
+		return nil, apperrors.ErrStoryViewersUnavailable
+	}
+
+	query := s.db.Preload("User").Where("story_id = ?", story.ID)
+	if cursor != nil {
+		query = query.Where("(viewed_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	var views []models.StoryView
+	if err := query.Order("viewed_at DESC, id DESC").
+		Limit(limit + 1).
+		Find(&views).Error; err != nil {
+		return nil, err
+	}
+
+	hasMore := len(views) > limit
+	if hasMore {
+		views = views[:limit]
+	}
+
+	viewers := make([]models.StoryViewerResponse, len(views))
+	for i, view := range views {
+		viewers[i] = models.StoryViewerResponse{
+			User:     view.User.ToResponse(),
+			ViewedAt: view.ViewedAt,
+		}
+	}
+
+	page := &pagination.Page{Items: viewers, HasMore: hasMore}
+	if hasMore {
+		last := views[len(views)-1]
+		page.NextCursor = pagination.NewCursor(last.ViewedAt, last.ID).Encode()
+	}
+	return page, nil
+}
+
+// DeleteStory permanently removes one of the user's stories along with its media, also
+// taking it out of any highlight. Unlike expired stories, deleted ones are not kept around.
+func (s *StoryService) DeleteStory(ctx context.Context, userID, storyID uuid.UUID) error {
+	var story models.Story
+	if err := s.db.Unscoped().First(&story, "id = ?", storyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrStoryNotFound
+		}
+		return err
+	}
+	if story.UserID != userID {
+		return apperrors.ErrUnauthorizedAction
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("story_id = ?", story.ID).Delete(&models.StoryView{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("story_id = ?", story.ID).Delete(&models.StorySticker{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("story_id = ?", story.ID).Delete(&models.Mention{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("story_id = ?", story.ID).Delete(&models.Notification{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("story_id = ?", story.ID).Delete(&models.HighlightItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Highlight{}).
+			Where("cover_story_id = ?", story.ID).
+			Update("cover_story_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&story).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	if story.MediaKey != "" {
+		if err := s.storage.Delete(ctx, story.MediaKey); err != nil {
+			log.Printf("story media cleanup: %v", err)
+		}
+	}
+	return nil
+}
+
+// GetStoryTray returns the live stories of the viewer and the accounts they follow, grouped
+// by user. The viewer's own stories come first, then users with stories the viewer has not
+// seen yet, each group ordered by its latest story.
+func (s *StoryService) GetStoryTray(viewerID uuid.UUID) ([]models.StoryTrayItem, error) {
+	var stories []models.Story
+	if err := s.db.Preload("User").
+		Where("expires_at > ?", time.Now()).
+		Where(`(stories.user_id = ? OR stories.user_id IN (SELECT following_id FROM follows WHERE follower_id = ? AND status = ?))`,
+			viewerID, viewerID, constants.FollowStatusAccepted).
+		Where(`stories.user_id NOT IN (SELECT blocked_id FROM blocks WHERE blocker_id = ?)
+			AND stories.user_id NOT IN (SELECT blocker_id FROM blocks WHERE blocked_id = ?)
+			AND stories.user_id NOT IN (SELECT muted_id FROM mutes WHERE muter_id = ?)`,
+			viewerID, viewerID, viewerID).
+		Order("created_at ASC, id ASC").
+		Find(&stories).Error; err != nil {
+		return nil, err
+	}
+
+	responses, err := s.buildStoryResponses(viewerID, stories)
+	if err != nil {
+		return nil, err
+	}
+
+	var tray []models.StoryTrayItem
+	groups := make(map[uuid.UUID]int)
+	latest := make(map[uuid.UUID]time.Time)
+	for _, story := range responses {
+		i, ok := groups[story.User.ID]
+		if !ok {
+			i = len(tray)
+			groups[story.User.ID] = i
+			tray = append(tray, models.StoryTrayItem{User: story.User})
+		}
+		tray[i].Stories = append(tray[i].Stories, story)
+		if !story.IsViewed {
+			tray[i].HasUnseen = true
+		}
+		latest[story.User.ID] = story.CreatedAt
+	}
+
+	sort.SliceStable(tray, func(i, j int) bool {
+		a, b := tray[i], tray[j]
+		if (a.User.ID == viewerID) != (b.User.ID == viewerID) {
+			return a.User.ID == viewerID
+		}
+		if a.HasUnseen != b.HasUnseen {
+			return a.HasUnseen
+		}
+		return latest[a.User.ID].After(latest[b.User.ID])
+	})
+	if tray == nil {
+		tray = []models.StoryTrayItem{}
+	}
+	return tray, nil
+}
+
+// SweepExpiredStories soft deletes stories past their expiry, which keeps them available
+// for highlights, and removes the media of stories expired for longer than
+// StoryMediaRetention unless they are in a highlight. At most StorySweepBatchSize files are
+// removed per sweep.
+//
+// Stories being added to a highlight are locked and skipped. The media keys are cleared
+// first and the files removed only once that committed, so a highlight never refers to
+// removed media; a file that fails to be removed is logged and left behind.
+func (s *StoryService) SweepExpiredStories(ctx context.Context) (int64, error) {
+	now := time.Now()
+	result := s.db.Where("expires_at <= ?", now).Delete(&models.Story{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	var keys []string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var stories []models.Story
+		if err := tx.Unscoped().
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Select("id", "media_key").
+			Where("deleted_at IS NOT NULL AND media_key <> '' AND expires_at <= ?", now.Add(-constants.StoryMediaRetention)).
+			Where("NOT EXISTS (SELECT 1 FROM highlight_items hi WHERE hi.story_id = stories.id)").
+			Limit(constants.StorySweepBatchSize).
+			Find(&stories).Error; err != nil {
+			return err
+		}
+		if len(stories) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, len(stories))
+		for i, story := range stories {
+			ids[i] = story.ID
+		}
+		var cleared []uuid.UUID
+		if err := tx.Raw(`
+			UPDATE stories SET media_key = ''
+			WHERE id IN ? AND NOT EXISTS (SELECT 1 FROM highlight_items hi WHERE hi.story_id = stories.id)
+			RETURNING id`, ids,
+		).Scan(&cleared).Error; err != nil {
+			return err
+		}
+
+		for _, story := range stories {
+			if slices.Contains(cleared, story.ID) {
+				keys = append(keys, story.MediaKey)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return result.RowsAffected, err
+	}
+
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			log.Printf("story sweeper: %v", err)
+		}
+	}
+
+	return result.RowsAffected, nil
+}
+
+// RunSweeper periodically sweeps expired stories until ctx is cancelled
+func (s *StoryService) RunSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.SweepExpiredStories(ctx); err != nil {
+				log.Printf("story sweeper: %v", err)
+			}
+		}
+	}
+}
+
+// ReplyToStory sends the story author a direct message that refers to the story
+func (s *StoryService) ReplyToStory(viewerID, storyID uuid.UUID, content string) (*models.MessageResponse, error) {
+	return s.sendStoryMessage(viewerID, storyID, constants.MessageKindStoryReply, content)
+}
+
+// ReactToStory sends the story author one of the quick reaction emojis as a direct message
+func (s *StoryService) ReactToStory(viewerID, storyID uuid.UUID, emoji string) (*models.MessageResponse, error) {
+	if !slices.Contains(constants.StoryQuickReactions, emoji) {
+		return nil, apperrors.ErrInvalidStoryReaction
+	}
+	return s.sendStoryMessage(viewerID, storyID, constants.MessageKindStoryReaction, emoji)
+}
+
+// sendStoryMessage checks the author's story reply policy and sends the message to their
+// direct conversation with the viewer
+func (s *StoryService) sendStoryMessage(viewerID, storyID uuid.UUID, kind, content string) (*models.MessageResponse, error) {
+	story, err := s.findViewableStory(viewerID, storyID)
+	if err != nil {
+		return nil, err
+	}
+	if story.UserID == viewerID {
+		return nil, apperrors.ErrCannotMessageSelf
+	}
+
+	switch story.User.StoryReplyPolicy {
+	case constants.StoryReplyPolicyOff:
+		return nil, apperrors.ErrStoryRepliesDisabled
+	case constants.StoryReplyPolicyFollowers:
+		var count int64
+		if err := s.db.Model(&models.Follow{}).
+			Where("follower_id = ? AND following_id = ? AND status = ?", viewerID, story.UserID, constants.FollowStatusAccepted).
+			Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, apperrors.ErrStoryRepliesRestricted
+		}
+	}
+
+	return s.messageService.SendStoryMessage(viewerID, story, kind, content)
+}
+
+// findViewableStory loads a live story the viewer is allowed to see. Hidden stories are
+// indistinguishable from missing ones.
+func (s *StoryService) findViewableStory(viewerID, storyID uuid.UUID) (*models.Story, error) {
+	var story models.Story
+	if err := s.db.Preload("User").First(&story, "id = ?", storyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrStoryNotFound
+		}
+		return nil, err
+	}
+
+	allowed, err := s.canViewStoriesOf(viewerID, &story.User)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, apperrors.ErrStoryNotFound
+	}
+	if story.UserID != viewerID && !story.ExpiresAt.After(time.Now()) {
+		return nil, apperrors.ErrStoryExpired
+	}
+	return &story, nil
+}
+
+// canViewStoriesOf applies the author's privacy and blocks. Stories and highlights of
+// public accounts are visible to everyone, those of private accounts only to accepted followers.
+func (s *StoryService) canViewStoriesOf(viewerID uuid.UUID, author *models.User) (bool, error) {
+	if author.ID == viewerID {
+		return true, nil
+	}
+
+	blocked, err := blockedAmong(s.db, viewerID, []uuid.UUID{author.ID})
+	if err != nil {
+		return false, err
+	}
+	if blocked[author.ID] {
+		return false, nil
+	}
+	if !author.IsPrivate {
+		return true, nil
+	}
+	if viewerID == uuid.Nil {
+		return false, nil
+	}
+
+	var count int64
+	err = s.db.Model(&models.Follow{}).
+		Where("follower_id = ? AND following_id = ? AND status = ?", viewerID, author.ID, constants.FollowStatusAccepted).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// buildStoryResponses renders stories with their mentions and whether the viewer has seen them
+func (s *StoryService) buildStoryResponses(viewerID uuid.UUID, stories []models.Story) ([]models.StoryResponse, error) {
+	responses := make([]models.StoryResponse, len(stories))
+	if len(stories) == 0 {
+		return responses, nil
+	}
+
+	ids := make([]uuid.UUID, len(stories))
+	for i, story := range stories {
+		ids[i] = story.ID
+	}
+
+	var viewedIDs []uuid.UUID
+	if err := s.db.Model(&models.StoryView{}).
+		Where("user_id = ? AND story_id IN ?", viewerID, ids).
+		Distinct().
+		Pluck("story_id", &viewedIDs).Error; err != nil {
+		return nil, err
+	}
+	viewed := make(map[uuid.UUID]bool, len(viewedIDs))
+	for _, id := range viewedIDs {
+		viewed[id] = true
+	}
+
+	mentions, err := loadMentions(s.db, "story_id", ids)
+	if err != nil {
+		return nil, err
+	}
+	stickers, err := s.loadStickers(viewerID, stories)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range stories {
+		stories[i].IsViewed = stories[i].UserID == viewerID || viewed[stories[i].ID]
+		responses[i] = stories[i].ToResponse()
+		responses[i].Mentions = mentions[stories[i].ID]
+		responses[i].Stickers = stickers[stories[i].ID]
+	}
+	return responses, nil
+}
+
+// validateMediaUpload checks the extension and size of an uploaded file against its media
+// type and returns the normalized extension. Images are also checked by content.
+func validateMediaUpload(file *multipart.FileHeader, mediaType string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+
+	allowed, maxSize := constants.AllowedImageExtensions, int64(constants.MaxPostImageSize)
+	if mediaType == constants.PostTypeVideo {
+		allowed, maxSize = constants.AllowedVideoExtensions, int64(constants.MaxVideoSize)
+	}
+	if !slices.Contains(allowed, ext) {
+		return "", apperrors.ErrInvalidFileType
+	}
+	if file.Size > maxSize {
+		return "", apperrors.ErrFileTooLarge
+	}
+
+	if mediaType == constants.PostTypeImage {
+		src, err := file.Open()
+		if err != nil {
+			return "", err
+		}
+		defer src.Close()
+
+		head := make([]byte, 512)
+		n, _ := src.Read(head)
+		if !strings.HasPrefix(http.DetectContentType(head[:n]), "image/") {
+			return "", apperrors.ErrInvalidFileType
+		}
+	}
+	return ext, nil
+}
@@ -0,0 +1,367 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/internal/utils"
+	"social-media-backend/pkg/constants"
+	apperrors "social-media-backend/pkg/errors"
+	"social-media-backend/pkg/pagination"
+)
+
+// AnswerSticker records the viewer's answer to a poll, question or slider sticker of a live
+// story. Each user answers a sticker once.
+func (s *StoryService) AnswerSticker(viewerID, storyID, stickerID uuid.UUID, req *models.StickerAnswerRequest) (*models.StickerResponse, error) {
+	story, err := s.findViewableStory(viewerID, storyID)
+	if err != nil {
+		return nil, err
+	}
+	sticker, err := findStorySticker(s.db, story.ID, stickerID)
+	if err != nil {
+		return nil, err
+	}
+
+	answer := models.StoryStickerAnswer{StickerID: sticker.ID, UserID: viewerID}
+	switch sticker.Type {
+	case constants.StickerTypePoll:
+		if req.OptionIndex == nil || *req.OptionIndex >= len(sticker.Data.Options) {
+			return nil, apperrors.ErrInvalidStickerAnswer
+		}
+		answer.OptionIndex = req.OptionIndex
+	case constants.StickerTypeSlider:
+		if req.Value == nil {
+			return nil, apperrors.ErrInvalidStickerAnswer
+		}
+		answer.Value = req.Value
+	case constants.StickerTypeQuestion:
+		answer.Text = strings.TrimSpace(req.Text)
+		if answer.Text == "" {
+			return nil, apperrors.ErrInvalidStickerAnswer
+		}
+	default:
+		return nil, apperrors.ErrInvalidStickerAnswer
+	}
+
+	if err := s.db.Create(&answer).Error; err != nil {
+		if isUniqueViolation(err) {
+			return nil, apperrors.ErrAlreadyAnswered
+		}
+		return nil, err
+	}
+
+	stickers, err := s.loadStickers(viewerID, []models.Story{*story})
+	if err != nil {
+		return nil, err
+	}
+	for _, response := range stickers[story.ID] {
+		if response.ID == sticker.ID {
+			return &response, nil
+		}
+	}
+	return nil, apperrors.ErrStickerNotFound
+}
+
+// GetStickerAnswers lists the answers to a sticker on one of the user's stories, most recent first
+func (s *StoryService) GetStickerAnswers(userID, storyID, stickerID uuid.UUID, cursor *pagination.Cursor, limit int) (*pagination.Page, error) {
+	var story models.Story
+	if err := s.db.Unscoped().First(&story, "id = ?", storyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrStoryNotFound
+		}
+		return nil, err
+	}
+	if story.UserID != userID {
+		return nil, apperrors.ErrUnauthorizedAction
+	}
+	sticker, err := findStorySticker(s.db, story.ID, stickerID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.db.Preload("User").Where("sticker_id = ?", sticker.ID)
+	if cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	var answers []models.StoryStickerAnswer
+	if err := query.Order("created_at DESC, id DESC").
+		Limit(limit + 1).
+		Find(&answers).Error; err != nil {
+		return nil, err
+	}
+
+	hasMore := len(answers) > limit
+	if hasMore {
+		answers = answers[:limit]
+	}
+
+	responses := make([]models.StickerAnswerResponse, len(answers))
+	for i, answer := range answers {
+		responses[i] = models.StickerAnswerResponse{
+			User:        answer.User.ToResponse(),
+			OptionIndex: answer.OptionIndex,
+			Value:       answer.Value,
+			Text:        answer.Text,
+			CreatedAt:   answer.CreatedAt,
+		}
+	}
+
+	page := &pagination.Page{Items: responses, HasMore: hasMore}
+	if hasMore {
+		last := answers[len(answers)-1]
+		page.NextCursor = pagination.NewCursor(last.CreatedAt, last.ID).Encode()
+	}
+	return page, nil
+}
+
+// createStickers validates and stores the stickers of a story being created in tx. It
+// returns the users of mention stickers who may be mentioned by the author.
+func createStickers(tx *gorm.DB, story *models.Story, reqs []models.StoryStickerRequest) ([]uuid.UUID, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+
+	var matches []utils.MentionMatch
+	stickers := make([]models.StorySticker, len(reqs))
+	for i, req := range reqs {
+		sticker, err := newSticker(req)
+		if err != nil {
+			return nil, err
+		}
+		sticker.StoryID = story.ID
+		sticker.Position = i + 1
+		stickers[i] = sticker
+
+		if sticker.Type == constants.StickerTypeMention {
+			matches = append(matches, utils.MentionMatch{Username: sticker.Data.Username})
+		}
+	}
+
+	var mentioned []uuid.UUID
+	if len(matches) > 0 {
+		users, err := resolveMentions(tx, story.UserID, matches)
+		if err != nil {
+			return nil, err
+		}
+		seen := map[uuid.UUID]bool{}
+		for i := range stickers {
+			if stickers[i].Type != constants.StickerTypeMention {
+				continue
+			}
+			user, ok := users[strings.ToLower(stickers[i].Data.Username)]
+			if !ok {
+				continue
+			}
+			stickers[i].Data.UserID = &user.ID
+			stickers[i].Data.Username = user.Username
+			if !seen[user.ID] {
+				seen[user.ID] = true
+				mentioned = append(mentioned, user.ID)
+			}
+		}
+	}
+
+	if err := tx.Create(&stickers).Error; err != nil {
+		return nil, err
+	}
+	return mentioned, nil
+}
+
+// newSticker checks that a sticker request carries what its type needs and keeps only that
+func newSticker(req models.StoryStickerRequest) (models.StorySticker, error) {
+	sticker := models.StorySticker{
+		Type:     req.Type,
+		X:        req.X,
+		Y:        req.Y,
+		Rotation: req.Rotation,
+		Scale:    req.Scale,
+	}
+	if sticker.Scale == 0 {
+		sticker.Scale = 1
+	}
+
+	question := strings.TrimSpace(req.Question)
+	switch req.Type {
+	case constants.StickerTypePoll:
+		if question == "" || len(req.Options) < 2 {
+			return sticker, apperrors.ErrInvalidSticker
+		}
+		sticker.Data = models.StickerData{Question: question, Options: req.Options}
+	case constants.StickerTypeQuestion:
+		if question == "" {
+			return sticker, apperrors.ErrInvalidSticker
+		}
+		sticker.Data = models.StickerData{Question: question}
+	case constants.StickerTypeSlider:
+		if req.Emoji == "" {
+			return sticker, apperrors.ErrInvalidSticker
+		}
+		sticker.Data = models.StickerData{Question: question, Emoji: req.Emoji}
+	case constants.StickerTypeMention:
+		username := strings.TrimPrefix(req.Username, "@")
+		if len(username) < constants.MinUsernameLength {
+			return sticker, apperrors.ErrInvalidSticker
+		}
+		sticker.Data = models.StickerData{Username: username}
+	case constants.StickerTypeLink:
+		if !strings.HasPrefix(req.URL, "https://") && !strings.HasPrefix(req.URL, "http://") {
+			return sticker, apperrors.ErrInvalidSticker
+		}
+		sticker.Data = models.StickerData{URL: req.URL}
+	case constants.StickerTypeHashtag:
+		name, ok := utils.NormalizeHashtag(req.Hashtag)
+		if !ok {
+			return sticker, apperrors.ErrInvalidSticker
+		}
+		sticker.Data = models.StickerData{Hashtag: name}
+	default:
+		return sticker, apperrors.ErrInvalidSticker
+	}
+	return sticker, nil
+}
+
+// loadStickers renders the stickers of stories for the viewer, with results where the viewer
+// may see them
+func (s *StoryService) loadStickers(viewerID uuid.UUID, stories []models.Story) (map[uuid.UUID][]models.StickerResponse, error) {
+	result := make(map[uuid.UUID][]models.StickerResponse)
+	if len(stories) == 0 {
+		return result, nil
+	}
+
+	authors := make(map[uuid.UUID]uuid.UUID, len(stories))
+	storyIDs := make([]uuid.UUID, len(stories))
+	for i, story := range stories {
+		storyIDs[i] = story.ID
+		authors[story.ID] = story.UserID
+	}
+
+	var stickers []models.StorySticker
+	if err := s.db.Where("story_id IN ?", storyIDs).
+		Order("position ASC").
+		Find(&stickers).Error; err != nil {
+		return nil, err
+	}
+	if len(stickers) == 0 {
+		return result, nil
+	}
+
+	var answerable []uuid.UUID
+	for _, sticker := range stickers {
+		if isAnswerable(sticker.Type) {
+			answerable = append(answerable, sticker.ID)
+		}
+	}
+
+	totals := map[uuid.UUID]int{}
+	averages := map[uuid.UUID]float64{}
+	optionCounts := map[uuid.UUID]map[int]int{}
+	answered := map[uuid.UUID]bool{}
+	if len(answerable) > 0 {
+		var stats []struct {
+			StickerID uuid.UUID
+			Total     int
+			Average   *float64
+		}
+		if err := s.db.Model(&models.StoryStickerAnswer{}).
+			Select("sticker_id, COUNT(*) AS total, AVG(value) AS average").
+			Where("sticker_id IN ?", answerable).
+			Group("sticker_id").
+			Scan(&stats).Error; err != nil {
+			return nil, err
+		}
+		for _, stat := range stats {
+			totals[stat.StickerID] = stat.Total
+			if stat.Average != nil {
+				averages[stat.StickerID] = *stat.Average
+			}
+		}
+
+		var options []struct {
+			StickerID   uuid.UUID
+			OptionIndex int
+			Count       int
+		}
+		if err := s.db.Model(&models.StoryStickerAnswer{}).
+			Select("sticker_id, option_index, COUNT(*) AS count").
+			Where("sticker_id IN ? AND option_index IS NOT NULL", answerable).
+			Group("sticker_id, option_index").
+			Scan(&options).Error; err != nil {
+			return nil, err
+		}
+		for _, option := range options {
+			if optionCounts[option.StickerID] == nil {
+				optionCounts[option.StickerID] = map[int]int{}
+			}
+			optionCounts[option.StickerID][option.OptionIndex] = option.Count
+		}
+
+		if viewerID != uuid.Nil {
+			var own []uuid.UUID
+			if err := s.db.Model(&models.StoryStickerAnswer{}).
+				Where("user_id = ? AND sticker_id IN ?", viewerID, answerable).
+				Pluck("sticker_id", &own).Error; err != nil {
+				return nil, err
+			}
+			for _, id := range own {
+				answered[id] = true
+			}
+		}
+	}
+
+	for _, sticker := range stickers {
+		response := models.StickerResponse{
+			ID:       sticker.ID,
+			Type:     sticker.Type,
+			X:        sticker.X,
+			Y:        sticker.Y,
+			Rotation: sticker.Rotation,
+			Scale:    sticker.Scale,
+			Data:     sticker.Data,
+			Answered: answered[sticker.ID],
+		}
+
+		isAuthor := authors[sticker.StoryID] == viewerID
+		showResults := isAuthor || (response.Answered && sticker.Type != constants.StickerTypeQuestion)
+		if isAnswerable(sticker.Type) && showResults {
+			results := &models.StickerResults{Total: totals[sticker.ID]}
+			switch sticker.Type {
+			case constants.StickerTypePoll:
+				results.OptionCounts = make([]int, len(sticker.Data.Options))
+				for i := range results.OptionCounts {
+					results.OptionCounts[i] = optionCounts[sticker.ID][i]
+				}
+			case constants.StickerTypeSlider:
+				if average, ok := averages[sticker.ID]; ok {
+					results.Average = &average
+				}
+			}
+			response.Results = results
+		}
+
+		result[sticker.StoryID] = append(result[sticker.StoryID], response)
+	}
+	return result, nil
+}
+
+func findStorySticker(db *gorm.DB, storyID, stickerID uuid.UUID) (*models.StorySticker, error) {
+	var sticker models.StorySticker
+	if err := db.First(&sticker, "id = ? AND story_id = ?", stickerID, storyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrStickerNotFound
+		}
+		return nil, err
+	}
+	return &sticker, nil
+}
+
+// isAnswerable reports whether viewers can answer stickers of the type
+func isAnswerable(stickerType string) bool {
+	return stickerType == constants.StickerTypePoll ||
+		stickerType == constants.StickerTypeQuestion ||
+		stickerType == constants.StickerTypeSlider
+}
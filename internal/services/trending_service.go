@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"social-media-backend/internal/models"
+	"social-media-backend/pkg/constants"
+	apperrors "social-media-backend/pkg/errors"
+)
+
+type TrendingService struct {
+	db  *gorm.DB
+	rdb *redis.Client
+}
+
+func NewTrendingService(db *gorm.DB, rdb *redis.Client) *TrendingService {
+	return &TrendingService{db: db, rdb: rdb}
+}
+
+// trendingRow is the usage of one hashtag aggregated over a window and its baseline
+type trendingRow struct {
+	HashtagID    uuid.UUID
+	Uses         int
+	Velocity     float64
+	BaselineUses int
+}
+
+// GetTrending returns the top hashtags of a window. Rankings are served from the cache
+// kept warm by RunRefresher and computed on the spot when the cache is cold or down.
+func (s *TrendingService) GetTrending(ctx context.Context, window string, limit int) ([]models.TrendingHashtagResponse, error) {
+	if _, ok := constants.TrendingWindows[window]; !ok {
+		return nil, apperrors.ErrInvalidTrendingWindow
+	}
+	if limit <= 0 {
+		limit = constants.DefaultTrendingLimit
+	}
+	if limit > constants.TrendingCandidates {
+		limit = constants.TrendingCandidates
+	}
+
+	ranked, ok := s.cached(ctx, window)
+	if !ok {
+		var err error
+		ranked, err = s.Refresh(ctx, window)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked, nil
+}
+
+// Refresh recomputes the ranking of a window and stores it in the cache
+func (s *TrendingService) Refresh(ctx context.Context, window string) ([]models.TrendingHashtagResponse, error) {
+	ranked, err := s.compute(window, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if s.rdb != nil {
+		payload, err := json.Marshal(ranked)
+		if err != nil {
+			return nil, err
+		}
+		key := fmt.Sprintf(constants.CacheKeyTrending, window)
+		if err := s.rdb.Set(ctx, key, payload, 2*constants.TrendingRefreshInterval).Err(); err != nil {
+			log.Printf("trending cache: %v", err)
+		}
+	}
+	return ranked, nil
+}
+
+// RunRefresher recomputes every window periodically and prunes old usage buckets
+// until ctx is cancelled
+func (s *TrendingService) RunRefresher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for window := range constants.TrendingWindows {
+			if _, err := s.Refresh(ctx, window); err != nil {
+				log.Printf("trending refresher: %s: %v", window, err)
+			}
+		}
+		cutoff := time.Now().Add(-constants.HashtagUsageRetention)
+		if err := s.db.Where("bucket_start < ?", cutoff).Delete(&models.HashtagUsage{}).Error; err != nil {
+			log.Printf("trending refresher: prune usage: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *TrendingService) cached(ctx context.Context, window string) ([]models.TrendingHashtagResponse, bool) {
+	if s.rdb == nil {
+		return nil, false
+	}
+
+	payload, err := s.rdb.Get(ctx, fmt.Sprintf(constants.CacheKeyTrending, window)).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			log.Printf("trending cache: %v", err)
+		}
+		return nil, false
+	}
+
+	var ranked []models.TrendingHashtagResponse
+	if err := json.Unmarshal(payload, &ranked); err != nil {
+		return nil, false
+	}
+	return ranked, true
+}
+
+// compute ranks hashtags by their decayed usage within the window relative to their
+// usage over the preceding baseline period. Each use decays with a half-life of half
+// the window, so a burst in the last hour outweighs the same count spread over the day,
+// and dividing by the baseline favours tags that are rising over tags that are always busy.
+func (s *TrendingService) compute(window string, now time.Time) ([]models.TrendingHashtagResponse, error) {
+	start, _, err := trendingBounds(window, now)
+	if err != nil {
+		return nil, err
+	}
+	length := constants.TrendingWindows[window]
+	baselineStart := start.Add(-length * constants.TrendingBaselineFactor)
+	halfLife := (length / 2).Seconds()
+
+	var rows []trendingRow
+	if err := s.db.Raw(`
+		SELECT hashtag_id,
+			SUM(count) FILTER (WHERE bucket_start >= @start) AS uses,
+			SUM(count * EXP(LN(0.5) * EXTRACT(EPOCH FROM (@now - bucket_start)) / @half_life)) FILTER (WHERE bucket_start >= @start) AS velocity,
+			COALESCE(SUM(count) FILTER (WHERE bucket_start < @start), 0) AS baseline_uses
+		FROM hashtag_usages
+		WHERE bucket_start >= @baseline_start
+		GROUP BY hashtag_id
+		HAVING SUM(count) FILTER (WHERE bucket_start >= @start) >= @min_uses`,
+		map[string]interface{}{
+			"start":          start,
+			"now":            now,
+			"half_life":      halfLife,
+			"baseline_start": baselineStart,
+			"min_uses":       constants.TrendingMinUses,
+		}).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	scores := make(map[uuid.UUID]float64, len(rows))
+	for _, row := range rows {
+		expected := float64(row.BaselineUses) / constants.TrendingBaselineFactor
+		scores[row.HashtagID] = row.Velocity / (expected + constants.TrendingBaselineSmoothing)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return scores[rows[i].HashtagID] > scores[rows[j].HashtagID]
+	})
+	if len(rows) > constants.TrendingCandidates {
+		rows = rows[:constants.TrendingCandidates]
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.HashtagID
+	}
+	var hashtags []models.Hashtag
+	if len(ids) > 0 {
+		if err := s.db.Where("id IN ?", ids).Find(&hashtags).Error; err != nil {
+			return nil, err
+		}
+	}
+	byID := make(map[uuid.UUID]models.Hashtag, len(hashtags))
+	for _, hashtag := range hashtags {
+		byID[hashtag.ID] = hashtag
+	}
+
+	ranked := make([]models.TrendingHashtagResponse, 0, len(rows))
+	for _, row := range rows {
+		hashtag, ok := byID[row.HashtagID]
+		if !ok {
+			continue
+		}
+		ranked = append(ranked, models.TrendingHashtagResponse{
+			HashtagResponse: hashtag.ToResponse(),
+			Uses:            row.Uses,
+			Score:           scores[row.HashtagID],
+		})
+	}
+	return ranked, nil
+}
+
+// trendingBounds returns the hourly buckets [start, end) covered by a window ending now
+func trendingBounds(window string, now time.Time) (time.Time, time.Time, error) {
+	length, ok := constants.TrendingWindows[window]
+	if !ok {
+		return time.Time{}, time.Time{}, apperrors.ErrInvalidTrendingWindow
+	}
+
+	end := now.UTC().Truncate(time.Hour).Add(time.Hour)
+	return end.Add(-length), end, nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"social-media-backend/internal/models"
+	apperrors "social-media-backend/pkg/errors"
+)
+
+type UserService struct {
+	db *gorm.DB
+}
+
+func NewUserService(db *gorm.DB) *UserService {
+	return &UserService{db: db}
+}
+
+// GetSettings returns the user's privacy settings
+func (s *UserService) GetSettings(userID uuid.UUID) (*models.SettingsResponse, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return &models.SettingsResponse{
+		MentionPolicy:    user.MentionPolicy,
+		Region:           user.Region,
+		StoryReplyPolicy: user.StoryReplyPolicy,
+		MessagePolicy:    user.MessagePolicy,
+	}, nil
+}
+
+// UpdateSettings changes the given privacy settings
+func (s *UserService) UpdateSettings(userID uuid.UUID, req *models.UpdateSettingsRequest) (*models.SettingsResponse, error) {
+	updates := map[string]interface{}{}
+	if req.MentionPolicy != nil {
+		updates["mention_policy"] = *req.MentionPolicy
+	}
+	if req.Region != nil {
+		updates["region"] = strings.ToUpper(*req.Region)
+	}
+	if req.StoryReplyPolicy != nil {
+		updates["story_reply_policy"] = *req.StoryReplyPolicy
+	}
+	if req.MessagePolicy != nil {
+		updates["message_policy"] = *req.MessagePolicy
+	}
+
+	if len(updates) > 0 {
+		if err := s.db.Model(&models.User{}).Where("id = ?", userID).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetSettings(userID)
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+
+	"social-media-backend/pkg/constants"
+)
+
+var hashtagFolder = cases.Fold()
+
+// ExtractHashtags returns the distinct normalized hashtags of text in order of appearance.
+// A hashtag starts with '#' at the beginning of a word and runs over letters, digits,
+// combining marks and underscores. Tags without a letter, such as #1, are ignored, and
+// tags longer than MaxHashtagLength are skipped rather than truncated.
+func ExtractHashtags(text string) []string {
+	var tags []string
+	seen := map[string]bool{}
+
+	runes := []rune(norm.NFC.String(text))
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '#' || (i > 0 && isHashtagRune(runes[i-1])) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && isHashtagRune(runes[end]) {
+			end++
+		}
+
+		if tag, ok := NormalizeHashtag(string(runes[i+1 : end])); ok && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+			if len(tags) == constants.MaxHashtagsPerPost {
+				break
+			}
+		}
+		i = end - 1
+	}
+	return tags
+}
+
+// NormalizeHashtag case-folds a hashtag name, with or without its leading '#', and
+// reports whether it is a valid tag
+func NormalizeHashtag(name string) (string, bool) {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "#")
+	name = norm.NFC.String(hashtagFolder.String(norm.NFKC.String(name)))
+
+	count := utf8.RuneCountInString(name)
+	if count == 0 || count > constants.MaxHashtagLength {
+		return "", false
+	}
+
+	hasLetter := false
+	for _, r := range name {
+		if !isHashtagRune(r) {
+			return "", false
+		}
+		if unicode.IsLetter(r) {
+			hasLetter = true
+		}
+	}
+	return name, hasLetter
+}
+
+func isHashtagRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
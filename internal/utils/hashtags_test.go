@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractHashtags(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "no hashtags",
+			text: "hello world",
+			want: nil,
+		},
+		{
+			name: "in order of appearance",
+			text: "#go is fun #backend",
+			want: []string{"go", "backend"},
+		},
+		{
+			name: "punctuation ends a tag",
+			text: "love #golang, #rust! (#zig)",
+			want: []string{"golang", "rust", "zig"},
+		},
+		{
+			name: "underscores and digits are kept",
+			text: "#web_dev2024",
+			want: []string{"web_dev2024"},
+		},
+		{
+			name: "duplicates differing in case are folded",
+			text: "#Go #GO #go",
+			want: []string{"go"},
+		},
+		{
+			name: "not at the start of a word",
+			text: "issue#42 a#b",
+			want: nil,
+		},
+		{
+			name: "tags without a letter are ignored",
+			text: "#1 #2024 #a1",
+			want: []string{"a1"},
+		},
+		{
+			name: "non latin letters",
+			text: "#café #東京",
+			want: []string{"café", "東京"},
+		},
+		{
+			name: "too long tags are skipped",
+			text: "#" + strings.Repeat("a", 101) + " #ok",
+			want: []string{"ok"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractHashtags(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractHashtags(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeHashtag(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{"#GoLang", "golang", true},
+		{"  rust  ", "rust", true},
+		{"#", "", false},
+		{"123", "", false},
+		{"has space", "", false},
+		{"Straße", "strasse", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NormalizeHashtag(tt.name)
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("NormalizeHashtag(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
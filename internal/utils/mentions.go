@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"social-media-backend/pkg/constants"
+)
+
+// MentionMatch is an @username found in a text. Offset and Length count characters (runes)
+// of the text as given, including the '@', so clients can link the exact span.
+type MentionMatch struct {
+	Username string
+	Offset   int
+	Length   int
+}
+
+// ExtractMentions returns the @mentions of text in order of appearance. A mention starts
+// with '@' at the beginning of a word, so e-mail addresses are not matched, and runs over
+// the letters and digits allowed in usernames. Every occurrence is returned for linking,
+// but at most MaxMentionsPerText distinct usernames are considered.
+func ExtractMentions(text string) []MentionMatch {
+	var matches []MentionMatch
+	seen := map[string]bool{}
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '@' || (i > 0 && (isUsernameRune(runes[i-1]) || runes[i-1] == '@')) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && isUsernameRune(runes[end]) {
+			end++
+		}
+
+		username := string(runes[i+1 : end])
+		if n := end - i - 1; n >= constants.MinUsernameLength && n <= constants.MaxUsernameLength {
+			key := strings.ToLower(username)
+			if !seen[key] && len(seen) == constants.MaxMentionsPerText {
+				break
+			}
+			seen[key] = true
+			matches = append(matches, MentionMatch{Username: username, Offset: i, Length: end - i})
+		}
+		i = end - 1
+	}
+	return matches
+}
+
+// isUsernameRune matches the characters accepted by the alphanum rule on registration
+func isUsernameRune(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
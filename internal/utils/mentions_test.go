@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"social-media-backend/pkg/constants"
+)
+
+func TestExtractMentions(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []MentionMatch
+	}{
+		{
+			name: "no mentions",
+			text: "hello world",
+			want: nil,
+		},
+		{
+			name: "single mention",
+			text: "hi @alice",
+			want: []MentionMatch{{Username: "alice", Offset: 3, Length: 6}},
+		},
+		{
+			name: "start of text",
+			text: "@bob123 look",
+			want: []MentionMatch{{Username: "bob123", Offset: 0, Length: 7}},
+		},
+		{
+			name: "trailing punctuation is not part of the username",
+			text: "thanks @alice, @bob! (@carol)",
+			want: []MentionMatch{
+				{Username: "alice", Offset: 7, Length: 6},
+				{Username: "bob", Offset: 15, Length: 4},
+				{Username: "carol", Offset: 22, Length: 6},
+			},
+		},
+		{
+			name: "email addresses are not mentions",
+			text: "mail alice@example.com",
+			want: nil,
+		},
+		{
+			name: "double at is not a mention",
+			text: "@@alice",
+			want: nil,
+		},
+		{
+			name: "too short",
+			text: "@ab",
+			want: nil,
+		},
+		{
+			name: "repeated mentions are all returned with their case",
+			text: "@Alice and @alice",
+			want: []MentionMatch{
+				{Username: "Alice", Offset: 0, Length: 6},
+				{Username: "alice", Offset: 11, Length: 6},
+			},
+		},
+		{
+			name: "offsets count runes",
+			text: "héllo @zoey!",
+			want: []MentionMatch{{Username: "zoey", Offset: 6, Length: 5}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractMentions(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractMentions(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractMentionsLimit(t *testing.T) {
+	var names []string
+	for i := 0; i <= constants.MaxMentionsPerText; i++ {
+		names = append(names, fmt.Sprintf("@user%03d", i))
+	}
+	// Mentioning an already counted user after the limit still links it
+	text := strings.Join(names, " ") + " @user000"
+
+	got := ExtractMentions(text)
+	if len(got) != constants.MaxMentionsPerText {
+		t.Fatalf("got %d mentions, want %d", len(got), constants.MaxMentionsPerText)
+	}
+	if last := got[len(got)-1].Username; last != fmt.Sprintf("user%03d", constants.MaxMentionsPerText-1) {
+		t.Errorf("last mention = %q", last)
+	}
+}
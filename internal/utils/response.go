@@ -0,0 +1,26 @@
+package utils
+
+import "github.com/gin-gonic/gin"
+
+// Response is the envelope used for every API response
+type Response struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+func SuccessResponse(c *gin.Context, statusCode int, message string, data interface{}) {
+	c.JSON(statusCode, Response{
+		Success: true,
+		Message: message,
+		Data:    data,
+	})
+}
+
+func ErrorResponse(c *gin.Context, statusCode int, message string) {
+	c.AbortWithStatusJSON(statusCode, Response{
+		Success: false,
+		Error:   message,
+	})
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"social-media-backend/internal/config"
+	"social-media-backend/internal/middleware"
+	"social-media-backend/internal/routes"
+	"social-media-backend/internal/services"
+	"social-media-backend/internal/storage"
+	"social-media-backend/pkg/constants"
+	"social-media-backend/pkg/pagination"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	pagination.SetSigningKey([]byte(cfg.Pagination.CursorSecret))
+
+	db, err := config.ConnectDatabase(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect database: %v", err)
+	}
+
+	if err := config.MigrateDatabase(db); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	rdb := config.ConnectRedis(cfg)
+	defer rdb.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Background jobs
+	pollService := services.NewPollService(db, services.NewPostService(db, services.NewNotificationService(db), nil))
+	go pollService.RunCloser(ctx, time.Minute)
+	trendingService := services.NewTrendingService(db, rdb)
+	go trendingService.RunRefresher(ctx, constants.TrendingRefreshInterval)
+	feedFanout := services.NewFeedFanout(db, rdb)
+	go feedFanout.Run(ctx)
+	hub := services.NewRealtimeHub(db, rdb)
+	go hub.Run(ctx)
+	mediaStorage := storage.NewLocalStorage(cfg.Upload.Path, constants.UploadURLPrefix)
+	storyService := services.NewStoryService(db, mediaStorage, services.NewNotificationService(db), services.NewMessageService(db, mediaStorage, hub))
+	go storyService.RunSweeper(ctx, constants.StorySweepInterval)
+
+	if cfg.Server.Environment == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	// gin.Default would log the WebSocket access token with the query string
+	router := gin.New()
+	router.Use(middleware.RequestLogger(), gin.Recovery())
+	routes.SetupRoutes(router, db, rdb, cfg, hub, feedFanout)
+
+	server := &http.Server{
+		Addr:    ":" + cfg.Server.Port,
+		Handler: router,
+	}
+
+	go func() {
+		log.Printf("Server starting on port %s", cfg.Server.Port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down server...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Fatalf("Server forced to shutdown: %v", err)
+	}
+}
@@ -1,5 +1,7 @@
 package constants
 
+import "time"
+
 const (
 	// User roles
 	RoleUser  = "user"
@@ -10,21 +12,180 @@ const (
 	PostTypeVideo = "video"
 	PostTypeText  = "text"
 
+	// Reaction types
+	ReactionLike  = "like"
+	ReactionLove  = "love"
+	ReactionLaugh = "laugh"
+	ReactionWow   = "wow"
+	ReactionSad   = "sad"
+	ReactionAngry = "angry"
+
+	// Comment threads
+	MaxCommentDepth   = 2 // Replies below this depth are attached to the parent instead
+	CommentSortNewest = "newest"
+	CommentSortOldest = "oldest"
+	CommentSortTop    = "top"
+
+	// Comment policies
+	CommentPolicyEveryone  = "everyone"
+	CommentPolicyFollowers = "followers"
+	CommentPolicyOff       = "off"
+
+	// Comment statuses
+	CommentStatusVisible       = "visible"
+	CommentStatusHidden        = "hidden"
+	CommentStatusPendingReview = "pending_review"
+
+	MaxCommentFilterKeywords = 100
+
+	// Mentions
+	MinUsernameLength  = 3  // Matches the registration rules
+	MaxUsernameLength  = 50 // Matches the users.username column
+	MaxMentionsPerText = 20
+
+	// Mention policies, who may @mention a user
+	MentionPolicyEveryone  = "everyone"
+	MentionPolicyFollowing = "following" // Only accounts the user follows
+	MentionPolicyNobody    = "nobody"
+
+	// Hashtags
+	MaxHashtagLength   = 100 // Matches the hashtags.name column
+	MaxHashtagsPerPost = 30
+	HashtagFeedWindow  = 7 * 24 * time.Hour // How far back followed hashtags reach into the home feed
+
+	// Home feed
+	FanoutFollowerThreshold = 10000 // Posts of accounts with more followers are pulled at read time
+	FeedCacheSize           = 500   // Entries kept in each cached feed
+	FeedCacheTTL            = 72 * time.Hour
+	FeedOverfetch           = 2 // Cached entries read per requested post, some may no longer be visible
+	FanoutWorkers           = 4
+	FanoutQueueSize         = 1000 // Posts waiting for fanout before new ones are dropped
+
+	// Feed modes
+	FeedModeChronological = "chronological"
+	FeedModeRanked        = "ranked"
+
+	// Ranked feed
+	RankingCandidateWindow       = 72 * time.Hour // Age of followed and hashtag candidates
+	RankingPopularWindow         = 48 * time.Hour // Age of popular candidates
+	RankingFollowedCandidates    = 300
+	RankingHashtagCandidates     = 100
+	RankingPopularCandidates     = 100
+	RankingAffinityWindow        = 30 * 24 * time.Hour // Interactions counted towards affinity
+	RankedFeedTTL                = 15 * time.Minute    // Lifetime of a ranked snapshot being paged through
+	MaxConsecutivePostsPerAuthor = 2
+
+	// Explore
+	ExploreWindow        = 7 * 24 * time.Hour
+	ExploreCacheSize     = 300
+	ExploreCacheTTL      = 10 * time.Minute
+	ExploreMinEngagement = 3   // Likes plus weighted comments and shares
+	ExploreRegionBoost   = 2.0 // Score multiplier for authors from the viewer's region
+	ExploreRegionGlobal  = "global"
+
+	// Trending hashtags
+	TrendingWindow1h          = "1h"
+	TrendingWindow24h         = "24h"
+	TrendingWindow7d          = "7d"
+	TrendingBaselineFactor    = 4   // Baseline spans this many windows before the current one
+	TrendingMinUses           = 3   // Fewer uses within the window never trend
+	TrendingBaselineSmoothing = 5.0 // Keeps tags without history from dominating
+	TrendingCandidates        = 50  // Ranked hashtags kept per window
+	DefaultTrendingLimit      = 10
+	TrendingRefreshInterval   = 5 * time.Minute
+	HashtagUsageRetention     = 35 * 24 * time.Hour
+
+	// Share types
+	ShareTypeRepost = "repost"
+	ShareTypeQuote  = "quote"
+
+	// Follow statuses
+	FollowStatusPending  = "pending"
+	FollowStatusAccepted = "accepted"
+
+	// Profile grid
+	MaxPinnedPosts = 3
+
+	// Polls
+	MinPollDuration = 5 * time.Minute
+	MaxPollDuration = 7 * 24 * time.Hour
+
 	// Story duration
 	StoryDuration = 24 // hours
 
+	// Stories
+	StoryMediaRetention = 30 * 24 * time.Hour // Media of expired stories is kept this long so they can still be highlighted
+	StorySweepInterval  = 5 * time.Minute
+	StorySweepBatchSize = 500
+	StoryViewersWindow  = 48 * time.Hour // How long after expiry authors can still see who viewed a story
+	MaxHighlightStories = 100
+
+	// Story stickers
+	StickerTypePoll     = "poll"
+	StickerTypeQuestion = "question"
+	StickerTypeSlider   = "slider"
+	StickerTypeMention  = "mention"
+	StickerTypeLink     = "link"
+	StickerTypeHashtag  = "hashtag"
+
+	// Story reply policies, who may reply or react to a user's stories
+	StoryReplyPolicyEveryone  = "everyone"
+	StoryReplyPolicyFollowers = "followers"
+	StoryReplyPolicyOff       = "off"
+
+	// Message kinds
+	MessageKindText          = "text"
+	MessageKindStoryReply    = "story_reply"
+	MessageKindStoryReaction = "story_reaction"
+	MessageKindSystem        = "system" // Membership and group changes
+
+	// Conversations
+	ConversationKindDirect = "direct"
+	ConversationKindGroup  = "group"
+	ParticipantRoleAdmin   = "admin"
+	ParticipantRoleMember  = "member"
+	MaxGroupParticipants   = 250
+
+	// Participant statuses
+	ParticipantStatusAccepted = "accepted"
+	ParticipantStatusPending  = "pending" // In the user's message requests
+
+	// Message policies, who may send message requests to a user. Accounts the user follows
+	// always reach their inbox.
+	MessagePolicyEveryone  = "everyone"
+	MessagePolicyFollowers = "followers" // Only the user's followers
+	MessagePolicyNobody    = "nobody"
+
+	// Real-time events
+	RealtimeEventMessage       = "message"
+	RealtimeEventMessageUnsent = "message_unsent"
+	RealtimeEventRead          = "read"
+	RealtimeEventTyping        = "typing"
+	RealtimeEventResync        = "resync_required" // Resume could not replay everything, refetch over HTTP
+	RealtimeEventError         = "error"
+	RealtimeEventPing          = "ping" // Sent every RealtimePingInterval, clients answer with a pong
+	RealtimeEventPong          = "pong"
+	RealtimeChannel            = "realtime:events" // Redis pub/sub channel shared by all instances
+	RealtimeResumeLimit        = 500               // Missed messages replayed on reconnect
+	RealtimeSendBuffer         = 256               // Events queued per connection before it is dropped as too slow
+	RealtimeWriteTimeout       = 10 * time.Second
+	RealtimePingInterval       = 30 * time.Second
+	RealtimeReadTimeout        = 2 * RealtimePingInterval // Connections silent for longer are closed
+
 	// Notification types
-	NotificationTypeLike    = "like"
-	NotificationTypeComment = "comment"
-	NotificationTypeFollow  = "follow"
-	NotificationTypeMention = "mention"
+	NotificationTypeLike       = "like"
+	NotificationTypeComment    = "comment"
+	NotificationTypeFollow     = "follow"
+	NotificationTypeMention    = "mention"
+	NotificationTypeShare      = "share"
+	NotificationTypePollClosed = "poll_closed"
 
 	// Pagination defaults
-	DefaultPage     = 1
 	DefaultPageSize = 20
 	MaxPageSize     = 100
 
 	// File upload
+	UploadURLPrefix     = "/uploads"
 	MaxProfileImageSize = 5 * 1024 * 1024  // 5MB
 	MaxPostImageSize    = 10 * 1024 * 1024 // 10MB
 	MaxVideoSize        = 50 * 1024 * 1024 // 50MB
@@ -33,6 +194,10 @@ const (
 	CacheKeyUserProfile = "user:profile:%s"
 	CacheKeyUserFeed    = "user:feed:%s"
 	CacheKeyPost        = "post:%s"
+	CacheKeyTrending    = "hashtags:trending:%s"
+	CacheKeyCelebrities = "feed:celebrities"
+	CacheKeyRankedFeed  = "feed:ranked:%s:%s"
+	CacheKeyExplore     = "explore:%s"
 
 	// Rate limiting
 	RateLimitAuth   = 5   // 5 requests per minute
@@ -46,4 +211,17 @@ var (
 
 	// Allowed video extensions
 	AllowedVideoExtensions = []string{".mp4", ".mov", ".avi", ".mkv"}
-)
\ No newline at end of file
+
+	// Trending windows and their lengths
+	TrendingWindows = map[string]time.Duration{
+		TrendingWindow1h:  time.Hour,
+		TrendingWindow24h: 24 * time.Hour,
+		TrendingWindow7d:  7 * 24 * time.Hour,
+	}
+
+	// Quick reactions offered on stories
+	StoryQuickReactions = []string{"😂", "😮", "😍", "😢", "👏", "🔥", "🎉", "💯"}
+
+	// Supported reaction types
+	ReactionTypes = []string{ReactionLike, ReactionLove, ReactionLaugh, ReactionWow, ReactionSad, ReactionAngry}
+)
@@ -10,34 +10,102 @@ var (
 	ErrInvalidToken       = errors.New("invalid token")
 
 	// User errors
-	ErrUserNotFound      = errors.New("user not found")
-	ErrUserAlreadyExists = errors.New("user already exists")
-	ErrEmailAlreadyUsed  = errors.New("email already in use")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrUserAlreadyExists   = errors.New("user already exists")
+	ErrEmailAlreadyUsed    = errors.New("email already in use")
 	ErrUsernameAlreadyUsed = errors.New("username already in use")
+	ErrPrivateAccount      = errors.New("this account is private")
 
 	// Post errors
-	ErrPostNotFound   = errors.New("post not found")
+	ErrPostNotFound       = errors.New("post not found")
 	ErrUnauthorizedAction = errors.New("unauthorized to perform this action")
+	ErrPostArchived       = errors.New("post is archived")
+	ErrPostNotArchived    = errors.New("post is not archived")
+	ErrPostAlreadyPinned  = errors.New("post is already pinned")
+	ErrPostNotPinned      = errors.New("post is not pinned")
+	ErrPinLimitReached    = errors.New("pinned posts limit reached")
+	ErrAlreadyReposted    = errors.New("post already reposted")
+	ErrNotReposted        = errors.New("post not reposted")
+	ErrCannotSharePost    = errors.New("this post cannot be shared")
+	ErrCannotEditRepost   = errors.New("reposts cannot be edited")
+
+	// Poll errors
+	ErrPollNotFound        = errors.New("poll not found")
+	ErrPollClosed          = errors.New("poll is closed")
+	ErrAlreadyVoted        = errors.New("already voted on this poll")
+	ErrInvalidPollOption   = errors.New("invalid poll option")
+	ErrInvalidPollDuration = errors.New("poll must close between 5 minutes and 7 days from now")
+
+	// Collection errors
+	ErrCollectionNotFound  = errors.New("collection not found")
+	ErrCollectionNameTaken = errors.New("collection name already in use")
+	ErrPostNotSaved        = errors.New("post is not saved")
+
+	// Feed errors
+	ErrInvalidFeedMode = errors.New("feed mode must be chronological or ranked")
+
+	// Hashtag errors
+	ErrHashtagNotFound       = errors.New("hashtag not found")
+	ErrInvalidTrendingWindow = errors.New("trending window must be one of 1h, 24h or 7d")
 
 	// Comment errors
-	ErrCommentNotFound = errors.New("comment not found")
+	ErrCommentNotFound      = errors.New("comment not found")
+	ErrInvalidParentComment = errors.New("parent comment does not belong to this post")
+	ErrCommentsDisabled     = errors.New("comments are turned off for this post")
+	ErrCommentsRestricted   = errors.New("only followers can comment on this post")
+	ErrCannotPinComment     = errors.New("only visible top level comments can be pinned")
+	ErrKeywordLimitReached  = errors.New("comment filter keyword limit reached")
+	ErrKeywordAlreadyExists = errors.New("keyword already in filter list")
 
 	// Follow errors
 	ErrAlreadyFollowing = errors.New("already following this user")
 	ErrNotFollowing     = errors.New("not following this user")
 	ErrCannotFollowSelf = errors.New("cannot follow yourself")
 
+	// Block and mute errors
+	ErrCannotBlockSelf = errors.New("cannot block yourself")
+	ErrCannotMuteSelf  = errors.New("cannot mute yourself")
+
 	// Like errors
-	ErrAlreadyLiked = errors.New("already liked")
-	ErrNotLiked     = errors.New("not liked yet")
+	ErrAlreadyLiked      = errors.New("already liked")
+	ErrNotLiked          = errors.New("not liked yet")
+	ErrInvalidReaction   = errors.New("invalid reaction type")
+	ErrInvalidLikeTarget = errors.New("exactly one of post_id or comment_id is required")
 
 	// Message errors
-	ErrMessageNotFound = errors.New("message not found")
+	ErrMessageNotFound   = errors.New("message not found")
 	ErrCannotMessageSelf = errors.New("cannot message yourself")
+	ErrCannotMessageUser = errors.New("cannot message this user")
+	ErrEmptyMessage      = errors.New("message must have content or media")
+
+	// Conversation errors
+	ErrConversationNotFound = errors.New("conversation not found")
+	ErrNotGroupConversation = errors.New("only available for group conversations")
+	ErrConversationFull     = errors.New("group participant limit reached")
+	ErrAlreadyParticipant   = errors.New("user is already in this conversation")
+	ErrNotParticipant       = errors.New("user is not in this conversation")
+	ErrLastAdmin            = errors.New("a group needs at least one admin")
+	ErrNotMessageRequest    = errors.New("conversation is not a message request")
 
 	// Story errors
-	ErrStoryNotFound = errors.New("story not found")
-	ErrStoryExpired  = errors.New("story has expired")
+	ErrStoryNotFound           = errors.New("story not found")
+	ErrStoryExpired            = errors.New("story has expired")
+	ErrStoryViewersUnavailable = errors.New("viewers are no longer available for this story")
+	ErrStoryMediaUnavailable   = errors.New("story media is no longer available")
+	ErrStoryRepliesDisabled    = errors.New("replies are turned off for this story")
+	ErrStoryRepliesRestricted  = errors.New("only followers can reply to this story")
+	ErrInvalidStoryReaction    = errors.New("invalid story reaction")
+
+	// Sticker errors
+	ErrStickerNotFound      = errors.New("sticker not found")
+	ErrInvalidSticker       = errors.New("invalid sticker")
+	ErrInvalidStickerAnswer = errors.New("invalid answer for this sticker")
+	ErrAlreadyAnswered      = errors.New("already answered this sticker")
+
+	// Highlight errors
+	ErrHighlightNotFound   = errors.New("highlight not found")
+	ErrHighlightFull       = errors.New("highlight story limit reached")
+	ErrStoryNotInHighlight = errors.New("story is not in this highlight")
 
 	// File errors
 	ErrInvalidFileType = errors.New("invalid file type")
@@ -0,0 +1,141 @@
+// Package pagination implements keyset pagination with opaque, signed cursors.
+//
+// A cursor marks the last item of a page by its (CreatedAt, ID) position, optionally
+// preceded by a ranking Value such as a like count or an offset into a ranked list.
+// Cursors are signed with HMAC-SHA256 so clients cannot forge positions, and their
+// content is not part of the API.
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"social-media-backend/pkg/constants"
+)
+
+// signatureSize is the number of HMAC bytes kept in a cursor
+const signatureSize = 16
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+var (
+	keyMu      sync.RWMutex
+	signingKey = randomKey()
+)
+
+// SetSigningKey sets the key cursors are signed with. Until it is called a random key is
+// used, so cursors only stay valid for the lifetime of the process.
+func SetSigningKey(key []byte) {
+	keyMu.Lock()
+	defer keyMu.Unlock()
+	signingKey = append([]byte(nil), key...)
+}
+
+// Cursor is a position in a list ordered by (Value, CreatedAt, ID)
+type Cursor struct {
+	Value     int64
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// NewCursor returns the cursor of an item in a list ordered by (created_at, id)
+func NewCursor(createdAt time.Time, id uuid.UUID) Cursor {
+	return Cursor{CreatedAt: createdAt, ID: id}
+}
+
+// NewRankedCursor returns the cursor of an item in a list ordered by (value, created_at, id)
+func NewRankedCursor(value int64, createdAt time.Time, id uuid.UUID) Cursor {
+	return Cursor{Value: value, CreatedAt: createdAt, ID: id}
+}
+
+// Encode returns the opaque, signed form of the cursor
+func (c Cursor) Encode() string {
+	payload := []byte(strconv.FormatInt(c.Value, 10) + "|" + c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String())
+	return base64.RawURLEncoding.EncodeToString(append(payload, sign(payload)...))
+}
+
+// Decode verifies and parses a cursor produced by Encode
+func Decode(token string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) <= signatureSize {
+		return nil, ErrInvalidCursor
+	}
+
+	payload, signature := raw[:len(raw)-signatureSize], raw[len(raw)-signatureSize:]
+	if !hmac.Equal(signature, sign(payload)) {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(payload), "|", 3)
+	if len(parts) != 3 {
+		return nil, ErrInvalidCursor
+	}
+	value, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[1])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Cursor{Value: value, CreatedAt: createdAt, ID: id}, nil
+}
+
+// Parse reads the cursor and limit request parameters. An empty cursor starts from the
+// top; the limit defaults to DefaultPageSize and is clamped to MaxPageSize.
+func Parse(token, limit string) (*Cursor, int, error) {
+	size, err := strconv.Atoi(limit)
+	if err != nil || size < 1 {
+		size = constants.DefaultPageSize
+	}
+	if size > constants.MaxPageSize {
+		size = constants.MaxPageSize
+	}
+
+	if token == "" {
+		return nil, size, nil
+	}
+	cursor, err := Decode(token)
+	if err != nil {
+		return nil, size, err
+	}
+	return cursor, size, nil
+}
+
+// Page is the response envelope of a paginated list
+type Page struct {
+	Items      interface{} `json:"items"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	HasMore    bool        `json:"has_more"`
+}
+
+func sign(payload []byte) []byte {
+	keyMu.RLock()
+	defer keyMu.RUnlock()
+
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write(payload)
+	return mac.Sum(nil)[:signatureSize]
+}
+
+func randomKey() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic("pagination: " + err.Error())
+	}
+	return key
+}
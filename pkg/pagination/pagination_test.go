@@ -0,0 +1,136 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"social-media-backend/pkg/constants"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	SetSigningKey([]byte("test-key"))
+	createdAt := time.Date(2026, 3, 4, 5, 6, 7, 891011121, time.FixedZone("CET", 3600))
+
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{"keyset", NewCursor(createdAt, uuid.New())},
+		{"ranked", NewRankedCursor(42, createdAt, uuid.New())},
+		{"negative value", NewRankedCursor(-7, createdAt, uuid.New())},
+		{"zero values", Cursor{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.cursor.Encode())
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if got.Value != tt.cursor.Value || got.ID != tt.cursor.ID || !got.CreatedAt.Equal(tt.cursor.CreatedAt) {
+				t.Errorf("Decode = %+v, want %+v", *got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeRejectsInvalid(t *testing.T) {
+	SetSigningKey([]byte("test-key"))
+	valid := NewRankedCursor(3, time.Now(), uuid.New()).Encode()
+	raw, _ := base64.RawURLEncoding.DecodeString(valid)
+
+	tampered := append([]byte(nil), raw...)
+	tampered[0] = '9'
+	badSignature := append([]byte(nil), raw...)
+	badSignature[len(badSignature)-1] ^= 0xff
+
+	forge := func(payload string) string {
+		return base64.RawURLEncoding.EncodeToString(append([]byte(payload), sign([]byte(payload))...))
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"not base64", "!!not-a-cursor!!"},
+		{"too short", base64.RawURLEncoding.EncodeToString([]byte("short"))},
+		{"tampered payload", base64.RawURLEncoding.EncodeToString(tampered)},
+		{"tampered signature", base64.RawURLEncoding.EncodeToString(badSignature)},
+		{"missing fields", forge("1|2026-01-01T00:00:00Z")},
+		{"bad value", forge("x|2026-01-01T00:00:00Z|" + uuid.NewString())},
+		{"bad time", forge("1|yesterday|" + uuid.NewString())},
+		{"bad id", forge("1|2026-01-01T00:00:00Z|not-a-uuid")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(tt.token); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Decode error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestDecodeRejectsOtherKey(t *testing.T) {
+	SetSigningKey([]byte("old-key"))
+	token := NewCursor(time.Now(), uuid.New()).Encode()
+
+	SetSigningKey([]byte("new-key"))
+	if _, err := Decode(token); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Decode error = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	SetSigningKey([]byte("test-key"))
+	cursor := NewCursor(time.Now(), uuid.New())
+	token := cursor.Encode()
+
+	tests := []struct {
+		name      string
+		token     string
+		limit     string
+		wantLimit int
+		wantID    uuid.UUID
+		wantErr   bool
+	}{
+		{"defaults", "", "", constants.DefaultPageSize, uuid.Nil, false},
+		{"explicit limit", "", "5", 5, uuid.Nil, false},
+		{"minimum limit", "", "1", 1, uuid.Nil, false},
+		{"zero limit", "", "0", constants.DefaultPageSize, uuid.Nil, false},
+		{"negative limit", "", "-3", constants.DefaultPageSize, uuid.Nil, false},
+		{"non-numeric limit", "", "ten", constants.DefaultPageSize, uuid.Nil, false},
+		{"maximum limit", "", strconv.Itoa(constants.MaxPageSize), constants.MaxPageSize, uuid.Nil, false},
+		{"limit above maximum", "", strconv.Itoa(constants.MaxPageSize + 1), constants.MaxPageSize, uuid.Nil, false},
+		{"with cursor", token, "10", 10, cursor.ID, false},
+		{"invalid cursor", "garbage", "10", 10, uuid.Nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, limit, err := Parse(tt.token, tt.limit)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Fatalf("Parse error = %v, want ErrInvalidCursor", err)
+				}
+			} else if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if limit != tt.wantLimit {
+				t.Errorf("limit = %d, want %d", limit, tt.wantLimit)
+			}
+			if tt.wantID == uuid.Nil {
+				if got != nil {
+					t.Errorf("cursor = %+v, want nil", *got)
+				}
+			} else if got == nil || got.ID != tt.wantID {
+				t.Errorf("cursor = %+v, want id %s", got, tt.wantID)
+			}
+		})
+	}
+}